@@ -0,0 +1,183 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//DefaultAccountNameMatchThreshold default minimal similarity score for account names matching
+const DefaultAccountNameMatchThreshold = 0.8
+
+//AccountValidationRequest struct
+type AccountValidationRequest struct {
+	ProviderId    string `json:"provider_id"`
+	AccountNumber string `json:"account_number"`
+	BankCode      string `json:"bank_code,omitempty"`
+}
+
+//Check is valid AccountValidationRequest parameters
+func (avr *AccountValidationRequest) isValid() error {
+	var err error
+	if avr.ProviderId == "" {
+		err = fmt.Errorf(`parameter "provider_id" is empty`)
+	} else if avr.AccountNumber == "" {
+		err = fmt.Errorf(`parameter "account_number" is empty`)
+	}
+	return err
+}
+
+//AccountValidationResponse struct
+type AccountValidationResponse struct {
+	ResponseBody
+	Data *AccountValidationResponseData `json:"data,omitempty"`
+}
+
+//AccountValidationResponseData struct
+type AccountValidationResponseData struct {
+	ProviderID    string `json:"provider_id"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+//MatchAccountName check is registered account name similar enough to the given one
+func (avd *AccountValidationResponseData) MatchAccountName(name string, threshold float64) bool {
+	return AccountNameMatchScore(avd.AccountName, name) >= threshold
+}
+
+//MatchPayoutRequest check is registered account name and number correspond to the payout request
+func (avd *AccountValidationResponseData) MatchPayoutRequest(req *PayoutRequest, threshold float64) bool {
+	if avd.AccountNumber != "" && avd.AccountNumber != req.AccountNumber {
+		return false
+	}
+	return avd.MatchAccountName(req.AccountName, threshold)
+}
+
+//AccountsResource wrapper
+type AccountsResource struct {
+	ResourceAbstract
+}
+
+//Validate account holder name lookup (see https://docs.dusupay.com/sending-money/payouts/account-verification)
+func (r *AccountsResource) Validate(ctx context.Context, req *AccountValidationRequest) (*AccountValidationResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("AccountsResource.Validate error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("AccountsResource.Validate error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/verify-account", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("AccountsResource.Validate error: %v", err)
+	}
+	var result AccountValidationResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("AccountsResource.Validate error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//AccountNameMatchScore returns similarity score (from 0 to 1) of two account holder names.
+//Case, punctuation and the order of the name parts are ignored.
+func AccountNameMatchScore(first string, second string) float64 {
+	a := normalizeAccountName(first)
+	b := normalizeAccountName(second)
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	joinedA := strings.Join(a, " ")
+	joinedB := strings.Join(b, " ")
+	if joinedA == joinedB {
+		return 1
+	}
+	score := stringSimilarity(joinedA, joinedB)
+	//the registered name often contains additional middle names, so compare the name parts too
+	if len(a) > 1 && len(b) > 1 {
+		tokens := tokensSimilarity(a, b)
+		if tokens > score {
+			score = tokens
+		}
+	}
+	return score
+}
+
+//normalizeAccountName split name to sorted upper case parts without punctuation
+func normalizeAccountName(name string) []string {
+	parts := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(parts)
+	return parts
+}
+
+//tokensSimilarity average best similarity of the shortest name parts list against the longest one
+func tokensSimilarity(a []string, b []string) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var total float64
+	for _, ta := range a {
+		var best float64
+		for _, tb := range b {
+			s := stringSimilarity(ta, tb)
+			if s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+	return total / float64(len(a))
+}
+
+//stringSimilarity Levenshtein distance based similarity
+func stringSimilarity(a string, b string) float64 {
+	ra := []rune(a)
+	rb := []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshteinDistance(ra, rb))/float64(maxLen)
+}
+
+//levenshteinDistance func
+func levenshteinDistance(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+//minInt func
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
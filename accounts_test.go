@@ -0,0 +1,133 @@
+package dusupay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type AccountsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AccountsTestSuite) TestAccountValidationRequestIsValidSuccess() {
+	request := AccountValidationRequest{ProviderId: "mtn_ug", AccountNumber: "256777111786"}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *AccountsTestSuite) TestAccountValidationRequestIsValidEmptyProviderId() {
+	request := AccountValidationRequest{AccountNumber: "256777111786"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "provider_id" is empty`, result.Error())
+}
+
+func (suite *AccountsTestSuite) TestAccountValidationRequestIsValidEmptyAccountNumber() {
+	request := AccountValidationRequest{ProviderId: "mtn_ug"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "account_number" is empty`, result.Error())
+}
+
+func (suite *AccountsTestSuite) TestAccountNameMatchScore() {
+	assert.Equal(suite.T(), float64(1), AccountNameMatchScore("JOHN DOE", "Doe, John"))
+	assert.Equal(suite.T(), float64(1), AccountNameMatchScore("JOHN DOE MUKASA", "John Doe"))
+	assert.True(suite.T(), AccountNameMatchScore("JOHN DOE", "Jon Doe") >= DefaultAccountNameMatchThreshold)
+	assert.True(suite.T(), AccountNameMatchScore("JOHN DOE", "Jane Smith") < DefaultAccountNameMatchThreshold)
+	assert.True(suite.T(), AccountNameMatchScore("JOHN DOE MUKASA", "Doe") < DefaultAccountNameMatchThreshold)
+	assert.Equal(suite.T(), float64(0), AccountNameMatchScore("JOHN DOE", ""))
+}
+
+func (suite *AccountsTestSuite) TestMatchPayoutRequest() {
+	data := &AccountValidationResponseData{AccountNumber: "256777111786", AccountName: "JOHN DOE MUKASA"}
+	assert.True(suite.T(), data.MatchPayoutRequest(&PayoutRequest{AccountNumber: "256777111786", AccountName: "John Mukasa"}, DefaultAccountNameMatchThreshold))
+	assert.False(suite.T(), data.MatchPayoutRequest(&PayoutRequest{AccountNumber: "256777111786", AccountName: "Peter Okello"}, DefaultAccountNameMatchThreshold))
+	assert.False(suite.T(), data.MatchPayoutRequest(&PayoutRequest{AccountNumber: "256777000000", AccountName: "John Mukasa"}, DefaultAccountNameMatchThreshold))
+}
+
+func TestAccountsTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountsTestSuite))
+}
+
+type AccountsResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *AccountsResource
+}
+
+func (suite *AccountsResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &AccountsResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *AccountsResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *AccountsResourceTestSuite) TestValidateSuccess() {
+	body, _ := LoadStubResponseData("stubs/accounts/validate/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/verify-account", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &AccountValidationRequest{ProviderId: "mtn_ug", AccountNumber: "256777111786"}
+	result, resp, err := suite.testable.Validate(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), http.StatusOK, result.Code)
+	assert.Equal(suite.T(), "success", result.Status)
+	assert.Equal(suite.T(), "Request completed successfully.", result.Message)
+	assert.Equal(suite.T(), "mtn_ug", result.Data.ProviderID)
+	assert.Equal(suite.T(), "256777111786", result.Data.AccountNumber)
+	assert.Equal(suite.T(), "JOHN DOE MUKASA", result.Data.AccountName)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *AccountsResourceTestSuite) TestValidateJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/verify-account", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &AccountValidationRequest{ProviderId: "mtn_ug", AccountNumber: "256777111786"}
+	result, resp, err := suite.testable.Validate(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *AccountsResourceTestSuite) TestValidateNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/verify-account", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &AccountValidationRequest{ProviderId: "mtn_ug", AccountNumber: "256777111786"}
+	result, resp, err := suite.testable.Validate(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *AccountsResourceTestSuite) TestValidateInvalidRequest() {
+	result, rsp, err := suite.testable.Validate(suite.ctx, &AccountValidationRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func TestAccountsResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountsResourceTestSuite))
+}
@@ -0,0 +1,226 @@
+package dusupay
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+//DefaultBalanceWatcherTolerance max unexplained balance change which is not reported
+const DefaultBalanceWatcherTolerance = 0.0001
+
+//BalanceSnapshot last known currency balance
+type BalanceSnapshot struct {
+	Currency CurrencyCode `json:"currency"`
+	Balance  float64      `json:"balance"`
+	//change since the previous poll
+	Delta     float64   `json:"delta"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//BalanceThresholdEvent balance threshold crossing
+type BalanceThresholdEvent struct {
+	Currency  CurrencyCode
+	Threshold float64
+	Previous  float64
+	Current   float64
+	//balance dropped below the threshold (false when it recovered above)
+	Below bool
+}
+
+//BalanceChangeEvent balance change without the matching expected transactions
+type BalanceChangeEvent struct {
+	Currency    CurrencyCode
+	Previous    float64
+	Current     float64
+	Delta       float64
+	Expected    float64
+	Unexplained float64
+}
+
+//BalanceWatcherHooks balance watcher callbacks (all callbacks are optional)
+type BalanceWatcherHooks struct {
+	OnThresholdCrossed func(event *BalanceThresholdEvent)
+	OnUnexpectedChange func(event *BalanceChangeEvent)
+	OnError            func(err error)
+}
+
+//NewBalanceWatcher create new balance watcher polling the balances on the interval
+func NewBalanceWatcher(balances BalancesFetcherInterface, interval time.Duration, hooks *BalanceWatcherHooks) *BalanceWatcher {
+	if hooks == nil {
+		hooks = &BalanceWatcherHooks{}
+	}
+	return &BalanceWatcher{
+		Interval:   interval,
+		Tolerance:  DefaultBalanceWatcherTolerance,
+		Now:        time.Now,
+		balances:   balances,
+		hooks:      hooks,
+		thresholds: make(map[CurrencyCode]float64),
+		snapshots:  make(map[CurrencyCode]*BalanceSnapshot),
+		expected:   make(map[CurrencyCode]float64),
+	}
+}
+
+//BalanceWatcher merchant balances watcher
+type BalanceWatcher struct {
+	Interval  time.Duration
+	Tolerance float64
+	Now       func() time.Time
+	mu        sync.Mutex
+	balances  BalancesFetcherInterface
+	hooks     *BalanceWatcherHooks
+	//low balance thresholds
+	thresholds map[CurrencyCode]float64
+	snapshots  map[CurrencyCode]*BalanceSnapshot
+	//expected balance changes registered since the previous poll
+	expected map[CurrencyCode]float64
+}
+
+//SetThreshold set currency low balance threshold
+func (bw *BalanceWatcher) SetThreshold(currency CurrencyCode, amount float64) *BalanceWatcher {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	bw.thresholds[currency] = amount
+	return bw
+}
+
+//ExpectChange register expected currency balance change (negative for debits) until the next poll
+func (bw *BalanceWatcher) ExpectChange(currency CurrencyCode, delta float64) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	bw.expected[currency] = roundAmount(bw.expected[currency] + delta)
+}
+
+//ExpectCollectionWebhook register completed collection credit as the expected change
+func (bw *BalanceWatcher) ExpectCollectionWebhook(webhook *CollectionWebhook) {
+	if TransactionStatusCode(webhook.TransactionStatus) != TransactionStatusCompleted {
+		return
+	}
+	bw.ExpectChange(CurrencyCode(webhook.AccountCurrency), webhook.TotalCredit)
+}
+
+//ExpectPayoutWebhook register completed payout debit as the expected change
+func (bw *BalanceWatcher) ExpectPayoutWebhook(webhook *PayoutWebhook) {
+	if TransactionStatusCode(webhook.TransactionStatus) != TransactionStatusCompleted {
+		return
+	}
+	bw.ExpectChange(CurrencyCode(webhook.AccountCurrency), -webhook.TotalDebit)
+}
+
+//GetSnapshot get last known currency balance
+func (bw *BalanceWatcher) GetSnapshot(currency CurrencyCode) (*BalanceSnapshot, bool) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	snapshot, ok := bw.snapshots[currency]
+	if !ok {
+		return nil, false
+	}
+	s := *snapshot
+	return &s, true
+}
+
+//Poll fetch balances once and fire the callbacks
+func (bw *BalanceWatcher) Poll(ctx context.Context) error {
+	//changes registered during the request belong to the next poll, so the expectations are taken before it
+	pending := bw.takeExpectations()
+	rsp, _, err := bw.balances.GetBalances(ctx)
+	if err != nil {
+		bw.restoreExpectations(pending)
+		return fmt.Errorf("BalanceWatcher.Poll error: %v", err)
+	}
+	bw.mu.Lock()
+	now := bw.Now()
+	var thresholdEvents []*BalanceThresholdEvent
+	var changeEvents []*BalanceChangeEvent
+	if rsp.Data != nil {
+		for _, item := range *rsp.Data {
+			currency := CurrencyCode(item.Currency)
+			previous, known := bw.snapshots[currency]
+			snapshot := &BalanceSnapshot{Currency: currency, Balance: item.Balance, UpdatedAt: now}
+			if known {
+				snapshot.Delta = roundAmount(item.Balance - previous.Balance)
+				expected := pending[currency]
+				unexplained := roundAmount(snapshot.Delta - expected)
+				if math.Abs(unexplained) > bw.Tolerance {
+					changeEvents = append(changeEvents, &BalanceChangeEvent{
+						Currency: currency, Previous: previous.Balance, Current: item.Balance,
+						Delta: snapshot.Delta, Expected: expected, Unexplained: unexplained,
+					})
+				}
+			}
+			delete(pending, currency)
+			if threshold, ok := bw.thresholds[currency]; ok {
+				below := item.Balance < threshold
+				if (!known && below) || (known && below != (previous.Balance < threshold)) {
+					event := &BalanceThresholdEvent{Currency: currency, Threshold: threshold, Current: item.Balance, Below: below}
+					if known {
+						event.Previous = previous.Balance
+					}
+					thresholdEvents = append(thresholdEvents, event)
+				}
+			}
+			bw.snapshots[currency] = snapshot
+		}
+	}
+	bw.mu.Unlock()
+	//expectations of the currencies missing in the response are kept for the next poll
+	bw.restoreExpectations(pending)
+	//callbacks are called outside of the lock, so they can register expectations or thresholds
+	if bw.hooks.OnUnexpectedChange != nil {
+		for _, event := range changeEvents {
+			bw.hooks.OnUnexpectedChange(event)
+		}
+	}
+	if bw.hooks.OnThresholdCrossed != nil {
+		for _, event := range thresholdEvents {
+			bw.hooks.OnThresholdCrossed(event)
+		}
+	}
+	return nil
+}
+
+//takeExpectations get and reset expected balance changes
+func (bw *BalanceWatcher) takeExpectations() map[CurrencyCode]float64 {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	expected := bw.expected
+	bw.expected = make(map[CurrencyCode]float64)
+	return expected
+}
+
+//restoreExpectations return not consumed expected balance changes
+func (bw *BalanceWatcher) restoreExpectations(expected map[CurrencyCode]float64) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	for currency, delta := range expected {
+		bw.expected[currency] = roundAmount(bw.expected[currency] + delta)
+	}
+}
+
+//Start poll balances on the interval until the context is done (poll errors are reported to the OnError callback)
+func (bw *BalanceWatcher) Start(ctx context.Context) error {
+	if bw.Interval <= 0 {
+		return fmt.Errorf(`BalanceWatcher.Start error: parameter "interval" must be positive`)
+	}
+	ticker := time.NewTicker(bw.Interval)
+	defer ticker.Stop()
+	for {
+		bw.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+//poll fetch balances and report the error
+func (bw *BalanceWatcher) poll(ctx context.Context) {
+	err := bw.Poll(ctx)
+	if err != nil && bw.hooks.OnError != nil {
+		bw.hooks.OnError(err)
+	}
+}
@@ -0,0 +1,173 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type BalanceWatcherTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	balances        *stubBalancesFetcher
+	thresholdEvents []*BalanceThresholdEvent
+	changeEvents    []*BalanceChangeEvent
+	errors          []error
+	testable        *BalanceWatcher
+}
+
+func (suite *BalanceWatcherTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.balances = &stubBalancesFetcher{balances: BalancesResponseData{{Currency: "UGX", Balance: 150000}, {Currency: "USD", Balance: 100}}}
+	suite.thresholdEvents = nil
+	suite.changeEvents = nil
+	suite.errors = nil
+	hooks := &BalanceWatcherHooks{
+		OnThresholdCrossed: func(event *BalanceThresholdEvent) {
+			suite.thresholdEvents = append(suite.thresholdEvents, event)
+		},
+		OnUnexpectedChange: func(event *BalanceChangeEvent) {
+			suite.changeEvents = append(suite.changeEvents, event)
+		},
+		OnError: func(err error) {
+			suite.errors = append(suite.errors, err)
+		},
+	}
+	suite.testable = NewBalanceWatcher(suite.balances, time.Millisecond, hooks)
+	suite.testable.SetThreshold(CurrencyCodeUGX, 100000)
+}
+
+func (suite *BalanceWatcherTestSuite) setBalance(currency string, balance float64) {
+	for _, item := range suite.balances.balances {
+		if item.Currency == currency {
+			item.Balance = balance
+		}
+	}
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollTracksDeltas() {
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	snapshot, ok := suite.testable.GetSnapshot(CurrencyCodeUGX)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), 150000.0, snapshot.Balance)
+	assert.Equal(suite.T(), 0.0, snapshot.Delta)
+
+	suite.testable.ExpectChange(CurrencyCodeUGX, -20000)
+	suite.setBalance("UGX", 130000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	snapshot, _ = suite.testable.GetSnapshot(CurrencyCodeUGX)
+	assert.Equal(suite.T(), -20000.0, snapshot.Delta)
+	assert.Len(suite.T(), suite.changeEvents, 0)
+	assert.Len(suite.T(), suite.thresholdEvents, 0)
+
+	_, ok = suite.testable.GetSnapshot(CurrencyCodeKES)
+	assert.False(suite.T(), ok)
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollUnexpectedChange() {
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	suite.testable.ExpectPayoutWebhook(&PayoutWebhook{AccountCurrency: "UGX", TotalDebit: 10000, TransactionStatus: "COMPLETED"})
+	suite.testable.ExpectCollectionWebhook(&CollectionWebhook{AccountCurrency: "UGX", TotalCredit: 5000, TransactionStatus: "PENDING"})
+	suite.setBalance("UGX", 120000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.changeEvents, 1)
+	event := suite.changeEvents[0]
+	assert.Equal(suite.T(), CurrencyCodeUGX, event.Currency)
+	assert.Equal(suite.T(), -30000.0, event.Delta)
+	assert.Equal(suite.T(), -10000.0, event.Expected)
+	assert.Equal(suite.T(), -20000.0, event.Unexplained)
+
+	//expectations are consumed by the poll
+	suite.setBalance("UGX", 125000)
+	suite.testable.ExpectCollectionWebhook(&CollectionWebhook{AccountCurrency: "UGX", TotalCredit: 5000, TransactionStatus: "COMPLETED"})
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.changeEvents, 1)
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollThresholdCrossing() {
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	suite.setBalance("UGX", 90000)
+	suite.testable.ExpectChange(CurrencyCodeUGX, -60000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.thresholdEvents, 1)
+	assert.True(suite.T(), suite.thresholdEvents[0].Below)
+	assert.Equal(suite.T(), 150000.0, suite.thresholdEvents[0].Previous)
+	assert.Equal(suite.T(), 90000.0, suite.thresholdEvents[0].Current)
+
+	//still below, no repeated alert
+	suite.setBalance("UGX", 80000)
+	suite.testable.ExpectChange(CurrencyCodeUGX, -10000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.thresholdEvents, 1)
+
+	//recovered
+	suite.setBalance("UGX", 180000)
+	suite.testable.ExpectChange(CurrencyCodeUGX, 100000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.thresholdEvents, 2)
+	assert.False(suite.T(), suite.thresholdEvents[1].Below)
+	assert.Len(suite.T(), suite.changeEvents, 0)
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollBelowThresholdOnStart() {
+	suite.setBalance("UGX", 50000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.thresholdEvents, 1)
+	assert.True(suite.T(), suite.thresholdEvents[0].Below)
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollError() {
+	suite.balances.err = errors.New("Unauthorized API access. Unknown Merchant")
+	err := suite.testable.Poll(suite.ctx)
+	assert.Equal(suite.T(), "BalanceWatcher.Poll error: Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollKeepsChangesExpectedDuringRequest() {
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	//payout webhook arrives while the balances are fetched, the debit is not reflected yet
+	suite.balances.onGet = func() {
+		suite.balances.onGet = nil
+		suite.testable.ExpectChange(CurrencyCodeUGX, -10000)
+	}
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.changeEvents, 0)
+
+	suite.setBalance("UGX", 140000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.changeEvents, 0)
+}
+
+func (suite *BalanceWatcherTestSuite) TestPollErrorKeepsExpectations() {
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	suite.testable.ExpectChange(CurrencyCodeUGX, -10000)
+	suite.balances.err = errors.New("timeout")
+	assert.Error(suite.T(), suite.testable.Poll(suite.ctx))
+
+	suite.balances.err = nil
+	suite.setBalance("UGX", 140000)
+	assert.NoError(suite.T(), suite.testable.Poll(suite.ctx))
+	assert.Len(suite.T(), suite.changeEvents, 0)
+}
+
+func (suite *BalanceWatcherTestSuite) TestStartInvalidInterval() {
+	suite.testable.Interval = 0
+	err := suite.testable.Start(suite.ctx)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `BalanceWatcher.Start error: parameter "interval" must be positive`, err.Error())
+}
+
+func (suite *BalanceWatcherTestSuite) TestStart() {
+	suite.balances.err = errors.New("timeout")
+	ctx, cancel := context.WithTimeout(suite.ctx, 20*time.Millisecond)
+	defer cancel()
+	err := suite.testable.Start(ctx)
+	assert.Equal(suite.T(), context.DeadlineExceeded, err)
+	assert.NotEmpty(suite.T(), suite.errors)
+}
+
+func TestBalanceWatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(BalanceWatcherTestSuite))
+}
@@ -18,13 +18,17 @@ type BanksFilter struct {
 
 //isValid check is valid BanksFilter parameters
 func (bf *BanksFilter) isValid() error {
-	var err error
-	if bf.Country == "" {
-		err = fmt.Errorf(`parameter "country_code" is empty`)
-	} else if bf.TransactionType == "" {
-		err = fmt.Errorf(`parameter "transaction_type" is empty`)
-	}
-	return err
+	return bf.Validate().first()
+}
+
+//Validate collect all BanksFilter parameters errors (nil when the filter is valid)
+func (bf *BanksFilter) Validate() ValidationErrors {
+	var errs ValidationErrors
+	errs.addIf(bf.Country == "", "country_code", ValidationReasonMissing, "is empty")
+	errs.addIf(bf.Country != "" && !validationCountryRegexp.MatchString(string(bf.Country)), "country_code", ValidationReasonBadFormat, `value "%s" is not ISO 3166-1 alpha-2 country code`, bf.Country)
+	errs.addIf(bf.TransactionType == "", "transaction_type", ValidationReasonMissing, "is empty")
+	errs.addIf(bf.TransactionType != "" && !isValidFilterTransactionType(bf.TransactionType), "transaction_type", ValidationReasonInvalidEnum, `value "%s" is not supported`, bf.TransactionType)
+	return errs
 }
 
 //buildPath
@@ -41,13 +45,16 @@ type BanksBranchesFilter struct {
 
 //isValid check is valid BanksBranchesFilter parameters
 func (bbf *BanksBranchesFilter) isValid() error {
-	var err error
-	if bbf.Country == "" {
-		err = fmt.Errorf(`parameter "country_code" is empty`)
-	} else if bbf.Bank == "" {
-		err = fmt.Errorf(`parameter "bank_code" is empty`)
-	}
-	return err
+	return bbf.Validate().first()
+}
+
+//Validate collect all BanksBranchesFilter parameters errors (nil when the filter is valid)
+func (bbf *BanksBranchesFilter) Validate() ValidationErrors {
+	var errs ValidationErrors
+	errs.addIf(bbf.Country == "", "country_code", ValidationReasonMissing, "is empty")
+	errs.addIf(bbf.Country != "" && !validationCountryRegexp.MatchString(string(bbf.Country)), "country_code", ValidationReasonBadFormat, `value "%s" is not ISO 3166-1 alpha-2 country code`, bbf.Country)
+	errs.addIf(bbf.Bank == "", "bank_code", ValidationReasonMissing, "is empty")
+	return errs
 }
 
 //buildPath
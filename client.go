@@ -15,7 +15,7 @@ func NewClientFromConfig(config *Config, cl *http.Client) (*Client, error) {
 		return nil, err
 	}
 	if cl == nil {
-		cl = &http.Client{}
+		cl = &http.Client{Timeout: config.Timeout}
 	}
 	transport := NewHttpTransport(config, cl)
 	return &Client{transport, config}, nil
@@ -51,6 +51,36 @@ func (c *Client) Banks() *BanksResource {
 	return &BanksResource{NewResourceAbstract(c.transport, c.config)}
 }
 
+//Accounts resource
+func (c *Client) Accounts() *AccountsResource {
+	return &AccountsResource{NewResourceAbstract(c.transport, c.config)}
+}
+
+//Forex resource
+func (c *Client) Forex() *ForexResource {
+	return &ForexResource{NewResourceAbstract(c.transport, c.config)}
+}
+
+//Fees resource
+func (c *Client) Fees() *FeesResource {
+	return &FeesResource{NewResourceAbstract(c.transport, c.config)}
+}
+
+//Transfers resource
+func (c *Client) Transfers() *TransfersResource {
+	return &TransfersResource{NewResourceAbstract(c.transport, c.config)}
+}
+
+//Settlements resource
+func (c *Client) Settlements() *SettlementsResource {
+	return &SettlementsResource{NewResourceAbstract(c.transport, c.config)}
+}
+
+//PaymentLinks resource
+func (c *Client) PaymentLinks() *PaymentLinksResource {
+	return &PaymentLinksResource{NewResourceAbstract(c.transport, c.config)}
+}
+
 //Webhooks resource
 func (c *Client) Webhooks() *WebhooksResource {
 	return &WebhooksResource{NewResourceAbstract(c.transport, c.config)}
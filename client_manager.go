@@ -0,0 +1,207 @@
+package dusupay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+//ClientAccount merchant account credentials with the routing rules
+type ClientAccount struct {
+	Name       string
+	Config     *Config
+	Countries  []CountryCode
+	Currencies []CurrencyCode
+}
+
+//NewClientManager create new multi-account client manager
+func NewClientManager() *ClientManager {
+	return &ClientManager{
+		clients:    make(map[string]*Client),
+		countries:  make(map[CountryCode]string),
+		currencies: make(map[CurrencyCode]string),
+	}
+}
+
+//ClientManager several merchant accounts clients keyed by name and routed by country or currency
+type ClientManager struct {
+	mu         sync.RWMutex
+	clients    map[string]*Client
+	countries  map[CountryCode]string
+	currencies map[CurrencyCode]string
+}
+
+//AddAccount create client for the merchant account and register its routing rules
+func (cm *ClientManager) AddAccount(account *ClientAccount, cl *http.Client) (*Client, error) {
+	if account.Name == "" {
+		return nil, fmt.Errorf(`ClientManager.AddAccount error: parameter "name" is empty`)
+	}
+	client, err := NewClientFromConfig(account.Config, cl)
+	if err != nil {
+		return nil, fmt.Errorf(`ClientManager.AddAccount error: account "%s": %v`, account.Name, err)
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if _, ok := cm.clients[account.Name]; ok {
+		return nil, fmt.Errorf(`ClientManager.AddAccount error: account "%s" already exists`, account.Name)
+	}
+	for _, country := range account.Countries {
+		if name, ok := cm.countries[country]; ok {
+			return nil, fmt.Errorf(`ClientManager.AddAccount error: country "%s" is already routed to account "%s"`, country, name)
+		}
+	}
+	for _, currency := range account.Currencies {
+		if name, ok := cm.currencies[currency]; ok {
+			return nil, fmt.Errorf(`ClientManager.AddAccount error: currency "%s" is already routed to account "%s"`, currency, name)
+		}
+	}
+	cm.clients[account.Name] = client
+	for _, country := range account.Countries {
+		cm.countries[country] = account.Name
+	}
+	for _, currency := range account.Currencies {
+		cm.currencies[currency] = account.Name
+	}
+	return client, nil
+}
+
+//Names get registered account names (sorted)
+func (cm *ClientManager) Names() []string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	names := make([]string, 0, len(cm.clients))
+	for name := range cm.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Get get client by account name
+func (cm *ClientManager) Get(name string) (*Client, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	client, ok := cm.clients[name]
+	if !ok {
+		return nil, fmt.Errorf(`ClientManager.Get error: account "%s" not found`, name)
+	}
+	return client, nil
+}
+
+//ForCountry get client routed for the country
+func (cm *ClientManager) ForCountry(country CountryCode) (*Client, error) {
+	cm.mu.RLock()
+	name, ok := cm.countries[country]
+	cm.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf(`ClientManager.ForCountry error: country "%s" is not routed`, country)
+	}
+	return cm.Get(name)
+}
+
+//ForCurrency get client routed for the currency
+func (cm *ClientManager) ForCurrency(currency CurrencyCode) (*Client, error) {
+	cm.mu.RLock()
+	name, ok := cm.currencies[currency]
+	cm.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf(`ClientManager.ForCurrency error: currency "%s" is not routed`, currency)
+	}
+	return cm.Get(name)
+}
+
+//ForCollection get client routed for the collection request currency or the provider country (e.g. mtn_ug -> UG)
+func (cm *ClientManager) ForCollection(req *CollectionRequest) (*Client, error) {
+	client, err := cm.forRequest(req.Currency, req.ProviderId)
+	if err != nil {
+		return nil, fmt.Errorf("ClientManager.ForCollection error: %v", err)
+	}
+	return client, nil
+}
+
+//ForPayout get client routed for the payout request currency or the provider country (e.g. mtn_ug -> UG)
+func (cm *ClientManager) ForPayout(req *PayoutRequest) (*Client, error) {
+	client, err := cm.forRequest(req.Currency, req.ProviderId)
+	if err != nil {
+		return nil, fmt.Errorf("ClientManager.ForPayout error: %v", err)
+	}
+	return client, nil
+}
+
+//forRequest get client routed for the currency, falling back to the provider country routing
+func (cm *ClientManager) forRequest(currency CurrencyCode, providerId string) (*Client, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	name, ok := cm.currencies[currency]
+	if !ok {
+		name, ok = cm.countries[providerCountry(providerId)]
+	}
+	if !ok {
+		return nil, fmt.Errorf(`neither currency "%s" nor provider "%s" country is routed`, currency, providerId)
+	}
+	return cm.clients[name], nil
+}
+
+//AggregatedBalances balances of all merchant accounts
+type AggregatedBalances struct {
+	Accounts map[string]*BalancesResponseData
+	//accounts balances requests errors
+	Errors map[string]error
+}
+
+//Total get currency balance total across the accounts
+func (ab *AggregatedBalances) Total(currency CurrencyCode) float64 {
+	var total float64
+	for _, balances := range ab.Accounts {
+		if balance, ok := balances.GetBalance(currency); ok {
+			total += balance
+		}
+	}
+	return roundAmount(total)
+}
+
+//Totals get balance totals per currency across the accounts
+func (ab *AggregatedBalances) Totals() map[CurrencyCode]float64 {
+	totals := make(map[CurrencyCode]float64)
+	for _, balances := range ab.Accounts {
+		for _, item := range *balances {
+			totals[CurrencyCode(item.Currency)] = roundAmount(totals[CurrencyCode(item.Currency)] + item.Balance)
+		}
+	}
+	return totals
+}
+
+//GetBalances get balances of all merchant accounts concurrently (failed accounts are reported in Errors)
+func (cm *ClientManager) GetBalances(ctx context.Context) *AggregatedBalances {
+	cm.mu.RLock()
+	clients := make(map[string]*Client, len(cm.clients))
+	for name, client := range cm.clients {
+		clients[name] = client
+	}
+	cm.mu.RUnlock()
+
+	result := &AggregatedBalances{Accounts: make(map[string]*BalancesResponseData), Errors: make(map[string]error)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, client := range clients {
+		wg.Add(1)
+		go func(name string, client *Client) {
+			defer wg.Done()
+			rsp, _, err := client.Merchants().GetBalances(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[name] = err
+				return
+			}
+			if rsp.Data == nil {
+				rsp.Data = &BalancesResponseData{}
+			}
+			result.Accounts[name] = rsp.Data
+		}(name, client)
+	}
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,131 @@
+package dusupay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type ClientManagerTestSuite struct {
+	suite.Suite
+	testable *ClientManager
+}
+
+func (suite *ClientManagerTestSuite) SetupTest() {
+	suite.testable = NewClientManager()
+	_, _ = suite.testable.AddAccount(&ClientAccount{
+		Name:       "uganda",
+		Config:     NewConfigSandbox("PublicKeyUG", "SecretKeyUG"),
+		Countries:  []CountryCode{CountryCodeUganda},
+		Currencies: []CurrencyCode{CurrencyCodeUGX, CurrencyCodeUSD},
+	}, nil)
+	_, _ = suite.testable.AddAccount(&ClientAccount{
+		Name:       "kenya",
+		Config:     NewConfigSandbox("PublicKeyKE", "SecretKeyKE"),
+		Countries:  []CountryCode{CountryCodeKenya},
+		Currencies: []CurrencyCode{CurrencyCodeKES},
+	}, nil)
+	httpmock.Activate()
+}
+
+func (suite *ClientManagerTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *ClientManagerTestSuite) TestAddAccountErrors() {
+	_, err := suite.testable.AddAccount(&ClientAccount{Config: NewConfigSandbox("p", "s")}, nil)
+	assert.Equal(suite.T(), `ClientManager.AddAccount error: parameter "name" is empty`, err.Error())
+	_, err = suite.testable.AddAccount(&ClientAccount{Name: "wrong", Config: &Config{Uri: SandboxAPIUrl}}, nil)
+	assert.Equal(suite.T(), `ClientManager.AddAccount error: account "wrong": parameter "public_key" is empty`, err.Error())
+	_, err = suite.testable.AddAccount(&ClientAccount{Name: "kenya", Config: NewConfigSandbox("p", "s")}, nil)
+	assert.Equal(suite.T(), `ClientManager.AddAccount error: account "kenya" already exists`, err.Error())
+	_, err = suite.testable.AddAccount(&ClientAccount{Name: "other", Config: NewConfigSandbox("p", "s"), Currencies: []CurrencyCode{CurrencyCodeKES}}, nil)
+	assert.Equal(suite.T(), `ClientManager.AddAccount error: currency "KES" is already routed to account "kenya"`, err.Error())
+	_, err = suite.testable.AddAccount(&ClientAccount{Name: "other", Config: NewConfigSandbox("p", "s"), Countries: []CountryCode{CountryCodeUganda}}, nil)
+	assert.Equal(suite.T(), `ClientManager.AddAccount error: country "UG" is already routed to account "uganda"`, err.Error())
+	assert.Equal(suite.T(), []string{"kenya", "uganda"}, suite.testable.Names())
+}
+
+func (suite *ClientManagerTestSuite) TestRouting() {
+	uganda, _ := suite.testable.Get("uganda")
+	kenya, _ := suite.testable.Get("kenya")
+
+	result, err := suite.testable.ForCountry(CountryCodeUganda)
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), uganda, result)
+
+	result, err = suite.testable.ForCurrency(CurrencyCodeKES)
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), kenya, result)
+
+	result, err = suite.testable.ForCollection(&CollectionRequest{Currency: CurrencyCodeUSD})
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), uganda, result)
+
+	result, err = suite.testable.ForPayout(&PayoutRequest{Currency: CurrencyCodeKES})
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), kenya, result)
+}
+
+func (suite *ClientManagerTestSuite) TestRoutingFallbackToProviderCountry() {
+	kenya, _ := suite.testable.Get("kenya")
+
+	result, err := suite.testable.ForCollection(&CollectionRequest{Currency: CurrencyCodeEUR, ProviderId: "mpesa_ke"})
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), kenya, result)
+
+	result, err = suite.testable.ForPayout(&PayoutRequest{Currency: CurrencyCodeEUR, ProviderId: "airtel_ke"})
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), kenya, result)
+
+	_, err = suite.testable.ForPayout(&PayoutRequest{Currency: CurrencyCodeTZS, ProviderId: "vodacom_tz"})
+	assert.Equal(suite.T(), `ClientManager.ForPayout error: neither currency "TZS" nor provider "vodacom_tz" country is routed`, err.Error())
+	_, err = suite.testable.ForCollection(&CollectionRequest{Currency: CurrencyCodeTZS, ProviderId: "international_tzs"})
+	assert.Equal(suite.T(), `ClientManager.ForCollection error: neither currency "TZS" nor provider "international_tzs" country is routed`, err.Error())
+}
+
+func (suite *ClientManagerTestSuite) TestRoutingErrors() {
+	_, err := suite.testable.Get("unknown")
+	assert.Equal(suite.T(), `ClientManager.Get error: account "unknown" not found`, err.Error())
+	_, err = suite.testable.ForCountry(CountryCodeTanzania)
+	assert.Equal(suite.T(), `ClientManager.ForCountry error: country "TZ" is not routed`, err.Error())
+	_, err = suite.testable.ForCurrency(CurrencyCodeTZS)
+	assert.Equal(suite.T(), `ClientManager.ForCurrency error: currency "TZS" is not routed`, err.Error())
+}
+
+func (suite *ClientManagerTestSuite) TestGetBalances() {
+	body, _ := LoadStubResponseData("stubs/merchants/balance/success.json")
+	errorBody, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, SandboxAPIUrl+"/v1/merchants/balance", func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("secret-key") == "SecretKeyUG" {
+			return httpmock.NewBytesResponse(http.StatusOK, body), nil
+		}
+		return httpmock.NewBytesResponse(http.StatusOK, errorBody), nil
+	})
+
+	result := suite.testable.GetBalances(context.Background())
+	assert.Len(suite.T(), result.Accounts, 1)
+	assert.Len(suite.T(), result.Errors, 1)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", result.Errors["kenya"].Error())
+	assert.Equal(suite.T(), 5475.816, result.Total(CurrencyCodeUGX))
+	assert.Equal(suite.T(), 0.0, result.Total(CurrencyCodeKES))
+	assert.Equal(suite.T(), map[CurrencyCode]float64{CurrencyCodeUGX: 5475.816, CurrencyCodeUSD: 12}, result.Totals())
+}
+
+func (suite *ClientManagerTestSuite) TestGetBalancesAggregated() {
+	body, _ := LoadStubResponseData("stubs/merchants/balance/success.json")
+	httpmock.RegisterResponder(http.MethodGet, SandboxAPIUrl+"/v1/merchants/balance", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result := suite.testable.GetBalances(context.Background())
+	assert.Len(suite.T(), result.Accounts, 2)
+	assert.Empty(suite.T(), result.Errors)
+	assert.Equal(suite.T(), 10951.632, result.Total(CurrencyCodeUGX))
+	assert.Equal(suite.T(), 24.0, result.Total(CurrencyCodeUSD))
+}
+
+func TestClientManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientManagerTestSuite))
+}
@@ -67,6 +67,48 @@ func (suite *ClientTestSuite) TestGetBanksResource() {
 	assert.NotEmpty(suite.T(), result)
 }
 
+func (suite *ClientTestSuite) TestGetAccountsResource() {
+	client, err := NewClientFromConfig(BuildStubConfig(), nil)
+	result := client.Accounts()
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+}
+
+func (suite *ClientTestSuite) TestGetForexResource() {
+	client, err := NewClientFromConfig(BuildStubConfig(), nil)
+	result := client.Forex()
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+}
+
+func (suite *ClientTestSuite) TestGetFeesResource() {
+	client, err := NewClientFromConfig(BuildStubConfig(), nil)
+	result := client.Fees()
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+}
+
+func (suite *ClientTestSuite) TestGetTransfersResource() {
+	client, err := NewClientFromConfig(BuildStubConfig(), nil)
+	result := client.Transfers()
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+}
+
+func (suite *ClientTestSuite) TestGetSettlementsResource() {
+	client, err := NewClientFromConfig(BuildStubConfig(), nil)
+	result := client.Settlements()
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+}
+
+func (suite *ClientTestSuite) TestGetPaymentLinksResource() {
+	client, err := NewClientFromConfig(BuildStubConfig(), nil)
+	result := client.PaymentLinks()
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), result)
+}
+
 func (suite *ClientTestSuite) TestGetWebhooksResource() {
 	client, err := NewClientFromConfig(BuildStubConfig(), nil)
 	result := client.Webhooks()
@@ -21,29 +21,42 @@ type CollectionRequest struct {
 	AccountName       string                `json:"account_name"`
 	AccountEmail      string                `json:"account_email"`
 	Voucher           string                `json:"voucher"`
+	Crypto            *CryptoParams         `json:"crypto,omitempty"`
 }
 
 //Check is valid CollectionRequest parameters
 func (cr *CollectionRequest) isValid() error {
-	var err error
-	if cr.Currency == "" {
-		err = fmt.Errorf(`parameter "currency" is empty`)
-	} else if cr.Amount == 0 {
-		err = fmt.Errorf(`parameter "amount" is empty`)
-	} else if cr.Method == "" {
-		err = fmt.Errorf(`parameter "method" is empty`)
-	} else if cr.ProviderId == "" {
-		err = fmt.Errorf(`parameter "provider_id" is empty`)
-	} else if cr.MerchantReference == "" {
-		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
-	} else if cr.Narration == "" {
-		err = fmt.Errorf(`parameter "narration" is empty`)
-	} else if cr.RedirectUrl == "" && cr.Method != TransactionMethodMobileMoney {
-		err = fmt.Errorf(`parameter "redirect_url" is empty`)
-	} else if cr.AccountNumber == "" && cr.Method == TransactionMethodMobileMoney {
-		err = fmt.Errorf(`parameter "account_number" is empty`)
+	return cr.Validate().first()
+}
+
+//Validate collect all CollectionRequest parameters errors (nil when the request is valid)
+func (cr *CollectionRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+	errs.addIf(cr.Currency == "", "currency", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.Currency != "" && !validationCurrencyRegexp.MatchString(string(cr.Currency)), "currency", ValidationReasonBadFormat, `value "%s" is not ISO 4217 currency code`, cr.Currency)
+	errs.addIf(cr.Amount == 0, "amount", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.Amount < 0, "amount", ValidationReasonOutOfRange, "is negative")
+	errs.addIf(cr.Method == "", "method", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.Method != "" && !isValidTransactionMethod(cr.Method), "method", ValidationReasonInvalidEnum, `value "%s" is not supported`, cr.Method)
+	errs.addIf(cr.ProviderId == "", "provider_id", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.MerchantReference == "", "merchant_reference", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.Narration == "", "narration", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.MobileMoneyHpp && cr.Method != TransactionMethodMobileMoney, "mobile_money_hpp", ValidationReasonNotAllowed, `is allowed for "%s" method only`, TransactionMethodMobileMoney)
+	errs.addIf(cr.RedirectUrl == "" && (cr.Method != TransactionMethodMobileMoney || cr.MobileMoneyHpp), "redirect_url", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.AccountNumber == "" && cr.Method == TransactionMethodMobileMoney && !cr.MobileMoneyHpp, "account_number", ValidationReasonMissing, "is empty")
+	errs.addIf(cr.AccountEmail != "" && !validationEmailRegexp.MatchString(cr.AccountEmail), "account_email", ValidationReasonBadFormat, "is not valid email")
+	if cr.Method == TransactionMethodCrypto {
+		errs = append(errs, cr.Crypto.Validate()...)
 	}
-	return err
+	return errs
+}
+
+//UseMobileMoneyHpp switch mobile money collection to the hosted payment page flow
+//(customer enters the phone number on the Dusupay page and comes back to redirectUrl)
+func (cr *CollectionRequest) UseMobileMoneyHpp(redirectUrl string) {
+	cr.Method = TransactionMethodMobileMoney
+	cr.MobileMoneyHpp = true
+	cr.RedirectUrl = redirectUrl
 }
 
 //CollectionResponse struct
@@ -54,25 +67,40 @@ type CollectionResponse struct {
 
 //CollectionResponseData struct
 type CollectionResponseData struct {
-	ID                int64   `json:"id"`
-	RequestAmount     float64 `json:"request_amount"`
-	RequestCurrency   string  `json:"request_currency"`
-	AccountAmount     float64 `json:"account_amount"`
-	AccountCurrency   string  `json:"account_currency"`
-	TransactionFee    float64 `json:"transaction_fee"`
-	TotalCredit       float64 `json:"total_credit"`
-	ProviderID        string  `json:"provider_id"`
-	MerchantReference string  `json:"merchant_reference"`
-	InternalReference string  `json:"internal_reference"`
-	TransactionStatus string  `json:"transaction_status"`
-	TransactionType   string  `json:"transaction_type"`
-	Message           string  `json:"message"`
-	CustomerCharged   bool    `json:"customer_charged"`
-	PaymentURL        string  `json:"payment_url"`
-	Instructions      []struct {
-		StepNo      string `json:"step_no"`
-		Description string `json:"description"`
-	} `json:"instructions"`
+	ID                int64                  `json:"id"`
+	RequestAmount     float64                `json:"request_amount"`
+	RequestCurrency   string                 `json:"request_currency"`
+	AccountAmount     float64                `json:"account_amount"`
+	AccountCurrency   string                 `json:"account_currency"`
+	TransactionFee    float64                `json:"transaction_fee"`
+	TotalCredit       float64                `json:"total_credit"`
+	ProviderID        string                 `json:"provider_id"`
+	MerchantReference string                 `json:"merchant_reference"`
+	InternalReference string                 `json:"internal_reference"`
+	TransactionStatus string                 `json:"transaction_status"`
+	TransactionType   string                 `json:"transaction_type"`
+	Message           string                 `json:"message"`
+	CustomerCharged   bool                   `json:"customer_charged"`
+	PaymentURL        string                 `json:"payment_url"`
+	Instructions      CollectionInstructions `json:"instructions"`
+	Crypto            *CryptoTransactionData `json:"crypto,omitempty"`
+}
+
+//IsHostedPaymentPage check is customer has to be redirected to the hosted payment page
+func (crd *CollectionResponseData) IsHostedPaymentPage() bool {
+	return crd.PaymentURL != ""
+}
+
+//NextAction what customer has to do next to complete the collection
+func (crd *CollectionResponseData) NextAction() *CollectionNextAction {
+	action := &CollectionNextAction{Type: CollectionNextActionWait, Instructions: crd.Instructions.steps()}
+	if crd.IsHostedPaymentPage() {
+		action.Type = CollectionNextActionRedirect
+		action.PaymentURL = crd.PaymentURL
+	} else if len(action.Instructions) > 0 {
+		action.Type = CollectionNextActionInstructions
+	}
+	return action
 }
 
 //CollectionsResource wrapper
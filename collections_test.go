@@ -155,6 +155,75 @@ func (suite *CollectionsTestSuite) TestCollectionRequestIsValidEmptyRedirectUrlM
 	assert.Equal(suite.T(), `parameter "account_number" is empty`, result.Error())
 }
 
+func (suite *CollectionsTestSuite) TestCollectionRequestIsValidMobileMoneyHppWithoutAccountNumber() {
+	request := CollectionRequest{
+		Currency:          CurrencyCodeUGX,
+		Amount:            100,
+		ProviderId:        "provider_id",
+		MerchantReference: "merchant_reference",
+		Narration:         "narration",
+	}
+	request.UseMobileMoneyHpp("redirect_url")
+	assert.Equal(suite.T(), TransactionMethodMobileMoney, request.Method)
+	assert.True(suite.T(), request.MobileMoneyHpp)
+	assert.Equal(suite.T(), "redirect_url", request.RedirectUrl)
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *CollectionsTestSuite) TestCollectionRequestIsValidMobileMoneyHppEmptyRedirectUrl() {
+	request := CollectionRequest{
+		Currency:          CurrencyCodeUGX,
+		Amount:            100,
+		Method:            TransactionMethodMobileMoney,
+		ProviderId:        "provider_id",
+		MerchantReference: "merchant_reference",
+		Narration:         "narration",
+		MobileMoneyHpp:    true,
+	}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "redirect_url" is empty`, result.Error())
+}
+
+func (suite *CollectionsTestSuite) TestCollectionRequestIsValidMobileMoneyHppWrongMethod() {
+	request := CollectionRequest{
+		Currency:          CurrencyCodeUGX,
+		Amount:            100,
+		Method:            TransactionMethodCard,
+		ProviderId:        "provider_id",
+		MerchantReference: "merchant_reference",
+		Narration:         "narration",
+		RedirectUrl:       "redirect_url",
+		MobileMoneyHpp:    true,
+	}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "mobile_money_hpp" is allowed for "MOBILE_MONEY" method only`, result.Error())
+}
+
+func (suite *CollectionsTestSuite) TestCollectionResponseDataNextActionRedirect() {
+	data := &CollectionResponseData{PaymentURL: "https://sandbox.dusupay.com/v1/complete-payment/foo"}
+	result := data.NextAction()
+	assert.True(suite.T(), data.IsHostedPaymentPage())
+	assert.Equal(suite.T(), CollectionNextActionRedirect, result.Type)
+	assert.Equal(suite.T(), "https://sandbox.dusupay.com/v1/complete-payment/foo", result.PaymentURL)
+}
+
+func (suite *CollectionsTestSuite) TestCollectionResponseDataNextActionInstructions() {
+	data := &CollectionResponseData{Instructions: CollectionInstructions{{StepNo: "1", Description: "foo"}}}
+	result := data.NextAction()
+	assert.False(suite.T(), data.IsHostedPaymentPage())
+	assert.Equal(suite.T(), CollectionNextActionInstructions, result.Type)
+	assert.Equal(suite.T(), data.Instructions, result.Instructions)
+}
+
+func (suite *CollectionsTestSuite) TestCollectionResponseDataNextActionWait() {
+	data := &CollectionResponseData{}
+	assert.Equal(suite.T(), CollectionNextActionWait, data.NextAction().Type)
+	data = &CollectionResponseData{Instructions: CollectionInstructions{nil}}
+	assert.Equal(suite.T(), CollectionNextActionWait, data.NextAction().Type)
+}
+
 func TestCollectionsTestSuite(t *testing.T) {
 	suite.Run(t, new(CollectionsTestSuite))
 }
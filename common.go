@@ -14,6 +14,12 @@ const TransactionTypePayout TransactionTypeCode = "PAYOUT"
 //TransactionTypeRefund const
 const TransactionTypeRefund TransactionTypeCode = "REFUND"
 
+//TransactionTypeTransfer const
+const TransactionTypeTransfer TransactionTypeCode = "TRANSFER"
+
+//TransactionTypeSettlement const
+const TransactionTypeSettlement TransactionTypeCode = "SETTLEMENT"
+
 //TransactionStatusCode type
 type TransactionStatusCode string
 
@@ -1,18 +1,45 @@
 package dusupay
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 //Config structure
 type Config struct {
-	Uri         string `json:"uri"`
-	PublicKey   string `json:"public_key"`
-	SecretKey   string `json:"secret_key"`
-	WebhookHash string `json:"webhook_hash"`
+	//explicit API environment (detected by the Uri when it is empty)
+	Environment Environment `json:"environment"`
+	Uri         string      `json:"uri"`
+	PublicKey   string      `json:"public_key"`
+	SecretKey   string      `json:"secret_key"`
+	WebhookHash string      `json:"webhook_hash"`
+	//path to the RSA public key used for the webhooks signature validation
+	WebhookPublicKeyPath string `json:"webhook_public_key_path"`
+	//http client timeout (used when the client is created without custom http client)
+	Timeout time.Duration `json:"timeout"`
+	//retries of the failed idempotent (GET) requests
+	MaxRetries int           `json:"max_retries"`
+	RetryDelay time.Duration `json:"retry_delay"`
+	//credentials source consulted per request (PublicKey and SecretKey are used when it is not set)
+	CredentialsProvider CredentialsProviderInterface `json:"-"`
+}
+
+//GetEnvironment get explicit or detected by the Uri environment
+func (c *Config) GetEnvironment() Environment {
+	if c.Environment != "" {
+		return c.Environment
+	}
+	return DetectEnvironment(c.Uri)
 }
 
 //IsSandbox check is sandbox environment
 func (c *Config) IsSandbox() bool {
-	return c.Uri != ProdAPIUrl
+	return c.GetEnvironment() == EnvironmentSandbox
+}
+
+//IsProduction check is production environment
+func (c *Config) IsProduction() bool {
+	return c.GetEnvironment() == EnvironmentProduction
 }
 
 //IsValid check is valid config parameters
@@ -20,10 +47,21 @@ func (c *Config) IsValid() error {
 	var err error
 	if c.Uri == "" {
 		err = fmt.Errorf(`parameter "uri" is empty`)
-	} else if c.PublicKey == "" {
+	} else if c.Environment != "" && !c.Environment.IsValid() {
+		err = fmt.Errorf(`parameter "environment" is invalid`)
+	} else if c.Environment != "" && c.Environment != EnvironmentCustom && DetectEnvironment(c.Uri) != c.Environment {
+		//e.g. sandbox credentials sent to the production API
+		err = fmt.Errorf(`parameter "uri" does not match %s environment`, c.Environment)
+	} else if c.CredentialsProvider == nil && c.PublicKey == "" {
 		err = fmt.Errorf(`parameter "public_key" is empty`)
-	} else if c.SecretKey == "" {
+	} else if c.CredentialsProvider == nil && c.SecretKey == "" {
 		err = fmt.Errorf(`parameter "secret_key" is empty`)
+	} else if c.Timeout < 0 {
+		err = fmt.Errorf(`parameter "timeout" is negative`)
+	} else if c.MaxRetries < 0 {
+		err = fmt.Errorf(`parameter "max_retries" is negative`)
+	} else if c.RetryDelay < 0 {
+		err = fmt.Errorf(`parameter "retry_delay" is negative`)
 	}
 	return err
 }
@@ -31,9 +69,32 @@ func (c *Config) IsValid() error {
 //NewConfig Create new config from credentials (Prod version)
 func NewConfig(publicKey string, secretKey string) *Config {
 	cfg := &Config{
-		Uri:       ProdAPIUrl,
-		PublicKey: publicKey,
-		SecretKey: secretKey,
+		Environment: EnvironmentProduction,
+		Uri:         ProdAPIUrl,
+		PublicKey:   publicKey,
+		SecretKey:   secretKey,
+	}
+	return cfg
+}
+
+//NewConfigForEnvironment Create new config from credentials for the environment (the API url is guarded against the environment)
+func NewConfigForEnvironment(environment Environment, publicKey string, secretKey string) *Config {
+	cfg := &Config{
+		Environment: environment,
+		Uri:         environment.GetBaseUrl(),
+		PublicKey:   publicKey,
+		SecretKey:   secretKey,
+	}
+	return cfg
+}
+
+//NewConfigCustom Create new config from credentials (Custom API url version)
+func NewConfigCustom(uri string, publicKey string, secretKey string) *Config {
+	cfg := &Config{
+		Environment: EnvironmentCustom,
+		Uri:         uri,
+		PublicKey:   publicKey,
+		SecretKey:   secretKey,
 	}
 	return cfg
 }
@@ -41,9 +102,10 @@ func NewConfig(publicKey string, secretKey string) *Config {
 //NewConfigSandbox Create new config from credentials (Sandbox version)
 func NewConfigSandbox(publicKey string, secretKey string) *Config {
 	cfg := &Config{
-		Uri:       SandboxAPIUrl,
-		PublicKey: publicKey,
-		SecretKey: secretKey,
+		Environment: EnvironmentSandbox,
+		Uri:         SandboxAPIUrl,
+		PublicKey:   publicKey,
+		SecretKey:   secretKey,
 	}
 	return cfg
 }
@@ -0,0 +1,321 @@
+package dusupay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//DefaultConfigEnvPrefix default environment variables prefix
+const DefaultConfigEnvPrefix = "DUSUPAY"
+
+//ConfigSourceError config loading error pointing at the offending source and key
+type ConfigSourceError struct {
+	//source description (e.g. "env", "file:/etc/dusupay.yaml", "secrets")
+	Source string
+	//source key (e.g. environment variable, file field or secret name)
+	Key string
+	Err error
+}
+
+//Error error interface implementation
+func (e *ConfigSourceError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf(`config source "%s" error: %v`, e.Source, e.Err)
+	}
+	return fmt.Sprintf(`config source "%s" key "%s" error: %v`, e.Source, e.Key, e.Err)
+}
+
+//Unwrap get original error
+func (e *ConfigSourceError) Unwrap() error {
+	return e.Err
+}
+
+//configValues raw config values (shared by all sources)
+type configValues struct {
+	Environment          string
+	Uri                  string
+	Sandbox              string
+	PublicKey            string
+	SecretKey            string
+	WebhookHash          string
+	WebhookPublicKeyPath string
+	Timeout              string
+	MaxRetries           string
+	RetryDelay           string
+}
+
+//configFile config file structure (scalars are accepted both as strings and as numbers/booleans)
+type configFile struct {
+	Environment          string      `json:"environment" yaml:"environment"`
+	Uri                  string      `json:"uri" yaml:"uri"`
+	Sandbox              interface{} `json:"sandbox" yaml:"sandbox"`
+	PublicKey            string      `json:"public_key" yaml:"public_key"`
+	SecretKey            string      `json:"secret_key" yaml:"secret_key"`
+	WebhookHash          string      `json:"webhook_hash" yaml:"webhook_hash"`
+	WebhookPublicKeyPath string      `json:"webhook_public_key_path" yaml:"webhook_public_key_path"`
+	Timeout              interface{} `json:"timeout" yaml:"timeout"`
+	MaxRetries           interface{} `json:"max_retries" yaml:"max_retries"`
+	RetryDelay           interface{} `json:"retry_delay" yaml:"retry_delay"`
+}
+
+//values convert config file to the raw config values
+func (cf *configFile) values() *configValues {
+	return &configValues{
+		Environment:          cf.Environment,
+		Uri:                  cf.Uri,
+		Sandbox:              stringifyConfigValue(cf.Sandbox),
+		PublicKey:            cf.PublicKey,
+		SecretKey:            cf.SecretKey,
+		WebhookHash:          cf.WebhookHash,
+		WebhookPublicKeyPath: cf.WebhookPublicKeyPath,
+		Timeout:              stringifyConfigValue(cf.Timeout),
+		MaxRetries:           stringifyConfigValue(cf.MaxRetries),
+		RetryDelay:           stringifyConfigValue(cf.RetryDelay),
+	}
+}
+
+//configKeys source keys of the config values
+type configKeys struct {
+	Environment, Uri, Sandbox, PublicKey, SecretKey, WebhookHash, WebhookPublicKeyPath, Timeout, MaxRetries, RetryDelay string
+}
+
+//buildConfig parse raw config values and validate the result
+func buildConfig(source string, keys *configKeys, values *configValues) (*Config, error) {
+	cfg := &Config{
+		Uri:                  values.Uri,
+		PublicKey:            values.PublicKey,
+		SecretKey:            values.SecretKey,
+		WebhookHash:          values.WebhookHash,
+		WebhookPublicKeyPath: values.WebhookPublicKeyPath,
+	}
+	if values.Environment != "" {
+		cfg.Environment = Environment(strings.ToLower(values.Environment))
+		if !cfg.Environment.IsValid() {
+			return nil, &ConfigSourceError{Source: source, Key: keys.Environment, Err: fmt.Errorf(`parameter "environment" is invalid`)}
+		}
+		if cfg.Uri == "" {
+			cfg.Uri = cfg.Environment.GetBaseUrl()
+		}
+		if cfg.Uri == "" {
+			return nil, &ConfigSourceError{Source: source, Key: keys.Uri, Err: fmt.Errorf(`parameter "uri" is empty for %s environment`, cfg.Environment)}
+		}
+	}
+	if values.Sandbox != "" {
+		sandbox, err := strconv.ParseBool(values.Sandbox)
+		if err != nil {
+			return nil, &ConfigSourceError{Source: source, Key: keys.Sandbox, Err: fmt.Errorf(`parameter "sandbox" is not a boolean`)}
+		}
+		if (sandbox && cfg.Environment == EnvironmentProduction) || (!sandbox && cfg.Environment == EnvironmentSandbox) {
+			return nil, &ConfigSourceError{Source: source, Key: keys.Sandbox, Err: fmt.Errorf(`parameter "sandbox" conflicts with %s environment`, cfg.Environment)}
+		}
+		if cfg.Uri == "" && sandbox {
+			cfg.Uri = SandboxAPIUrl
+		}
+	}
+	if cfg.Uri == "" {
+		cfg.Uri = ProdAPIUrl
+	}
+	var err error
+	if cfg.Timeout, err = parseConfigDuration(values.Timeout); err != nil {
+		return nil, &ConfigSourceError{Source: source, Key: keys.Timeout, Err: fmt.Errorf(`parameter "timeout" is not a duration`)}
+	}
+	if cfg.RetryDelay, err = parseConfigDuration(values.RetryDelay); err != nil {
+		return nil, &ConfigSourceError{Source: source, Key: keys.RetryDelay, Err: fmt.Errorf(`parameter "retry_delay" is not a duration`)}
+	}
+	if values.MaxRetries != "" {
+		if cfg.MaxRetries, err = strconv.Atoi(values.MaxRetries); err != nil {
+			return nil, &ConfigSourceError{Source: source, Key: keys.MaxRetries, Err: fmt.Errorf(`parameter "max_retries" is not an integer`)}
+		}
+	}
+	if values.WebhookPublicKeyPath != "" {
+		if _, err := os.Stat(values.WebhookPublicKeyPath); err != nil {
+			return nil, &ConfigSourceError{Source: source, Key: keys.WebhookPublicKeyPath, Err: fmt.Errorf(`parameter "webhook_public_key_path" is not readable: %v`, err)}
+		}
+	}
+	err = cfg.IsValid()
+	if err != nil {
+		return nil, &ConfigSourceError{Source: source, Key: keys.byParameter(err), Err: err}
+	}
+	return cfg, nil
+}
+
+//byParameter get source key of the config validation error parameter
+func (ck *configKeys) byParameter(err error) string {
+	parameters := map[string]string{
+		`"environment"`: ck.Environment,
+		`"uri"`:         ck.Uri,
+		`"public_key"`:  ck.PublicKey,
+		`"secret_key"`:  ck.SecretKey,
+		`"timeout"`:     ck.Timeout,
+		`"max_retries"`: ck.MaxRetries,
+		`"retry_delay"`: ck.RetryDelay,
+	}
+	for parameter, key := range parameters {
+		if strings.Contains(err.Error(), parameter) {
+			return key
+		}
+	}
+	return ""
+}
+
+//LoadConfigFromEnv load config from the environment variables with the prefix (e.g. DUSUPAY_PUBLIC_KEY, DUSUPAY_SANDBOX, DUSUPAY_TIMEOUT=30s)
+func LoadConfigFromEnv(prefix string) (*Config, error) {
+	if prefix == "" {
+		prefix = DefaultConfigEnvPrefix
+	}
+	keys := &configKeys{
+		Environment:          prefix + "_ENVIRONMENT",
+		Uri:                  prefix + "_URI",
+		Sandbox:              prefix + "_SANDBOX",
+		PublicKey:            prefix + "_PUBLIC_KEY",
+		SecretKey:            prefix + "_SECRET_KEY",
+		WebhookHash:          prefix + "_WEBHOOK_HASH",
+		WebhookPublicKeyPath: prefix + "_WEBHOOK_PUBLIC_KEY_PATH",
+		Timeout:              prefix + "_TIMEOUT",
+		MaxRetries:           prefix + "_MAX_RETRIES",
+		RetryDelay:           prefix + "_RETRY_DELAY",
+	}
+	values := &configValues{
+		Environment:          os.Getenv(keys.Environment),
+		Uri:                  os.Getenv(keys.Uri),
+		Sandbox:              os.Getenv(keys.Sandbox),
+		PublicKey:            os.Getenv(keys.PublicKey),
+		SecretKey:            os.Getenv(keys.SecretKey),
+		WebhookHash:          os.Getenv(keys.WebhookHash),
+		WebhookPublicKeyPath: os.Getenv(keys.WebhookPublicKeyPath),
+		Timeout:              os.Getenv(keys.Timeout),
+		MaxRetries:           os.Getenv(keys.MaxRetries),
+		RetryDelay:           os.Getenv(keys.RetryDelay),
+	}
+	return buildConfig("env", keys, values)
+}
+
+//LoadConfigFromFile load config from the JSON (.json) or YAML (.yaml, .yml) file
+func LoadConfigFromFile(path string) (*Config, error) {
+	source := "file:" + path
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &ConfigSourceError{Source: source, Err: err}
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return loadConfigFromData(source, data, json.Unmarshal)
+	case ".yaml", ".yml":
+		return loadConfigFromData(source, data, yaml.Unmarshal)
+	}
+	return nil, &ConfigSourceError{Source: source, Err: fmt.Errorf(`unsupported config file extension "%s"`, filepath.Ext(path))}
+}
+
+//LoadConfigFromJSON load config from the JSON data
+func LoadConfigFromJSON(data []byte) (*Config, error) {
+	return loadConfigFromData("json", data, json.Unmarshal)
+}
+
+//LoadConfigFromYAML load config from the YAML data
+func LoadConfigFromYAML(data []byte) (*Config, error) {
+	return loadConfigFromData("yaml", data, yaml.Unmarshal)
+}
+
+//loadConfigFromData decode config file data
+func loadConfigFromData(source string, data []byte, unmarshal func([]byte, interface{}) error) (*Config, error) {
+	var file configFile
+	err := unmarshal(data, &file)
+	if err != nil {
+		return nil, &ConfigSourceError{Source: source, Err: err}
+	}
+	keys := &configKeys{
+		Environment:          "environment",
+		Uri:                  "uri",
+		Sandbox:              "sandbox",
+		PublicKey:            "public_key",
+		SecretKey:            "secret_key",
+		WebhookHash:          "webhook_hash",
+		WebhookPublicKeyPath: "webhook_public_key_path",
+		Timeout:              "timeout",
+		MaxRetries:           "max_retries",
+		RetryDelay:           "retry_delay",
+	}
+	return buildConfig(source, keys, file.values())
+}
+
+//SecretProviderInterface secret store interface (vault, cloud secret managers etc.)
+type SecretProviderInterface interface {
+	GetSecret(ctx context.Context, name string) (string, error)
+}
+
+//StaticSecretProvider in-memory secrets provider
+type StaticSecretProvider map[string]string
+
+//GetSecret get secret by name
+func (sp StaticSecretProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	secret, ok := sp[name]
+	if !ok {
+		return "", fmt.Errorf(`secret "%s" not found`, name)
+	}
+	return secret, nil
+}
+
+//ConfigSecretNames secret names of the config credentials (empty names are not loaded)
+type ConfigSecretNames struct {
+	PublicKey   string
+	SecretKey   string
+	WebhookHash string
+}
+
+//LoadConfigSecrets populate config credentials from the secret provider and validate the result
+func LoadConfigSecrets(ctx context.Context, cfg *Config, provider SecretProviderInterface, names *ConfigSecretNames) error {
+	secrets := []struct {
+		name  string
+		value *string
+	}{
+		{names.PublicKey, &cfg.PublicKey},
+		{names.SecretKey, &cfg.SecretKey},
+		{names.WebhookHash, &cfg.WebhookHash},
+	}
+	for _, secret := range secrets {
+		if secret.name == "" {
+			continue
+		}
+		value, err := provider.GetSecret(ctx, secret.name)
+		if err != nil {
+			return &ConfigSourceError{Source: "secrets", Key: secret.name, Err: err}
+		}
+		*secret.value = value
+	}
+	err := cfg.IsValid()
+	if err != nil {
+		keys := &configKeys{PublicKey: names.PublicKey, SecretKey: names.SecretKey}
+		return &ConfigSourceError{Source: "secrets", Key: keys.byParameter(err), Err: err}
+	}
+	return nil
+}
+
+//ParseConfigDuration parse duration given as Go duration string or integer seconds
+func parseConfigDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
+
+//StringifyConfigValue convert decoded config scalar to string
+func stringifyConfigValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if f, ok := value.(float64); ok && f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,220 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+	"time"
+)
+
+type ConfigLoaderTestSuite struct {
+	suite.Suite
+	env []string
+}
+
+func (suite *ConfigLoaderTestSuite) setEnv(values map[string]string) {
+	for key, value := range values {
+		_ = os.Setenv(key, value)
+		suite.env = append(suite.env, key)
+	}
+}
+
+func (suite *ConfigLoaderTestSuite) TearDownTest() {
+	for _, key := range suite.env {
+		_ = os.Unsetenv(key)
+	}
+	suite.env = nil
+}
+
+func (suite *ConfigLoaderTestSuite) assertSourceError(err error, source string, key string) {
+	var sourceErr *ConfigSourceError
+	assert.True(suite.T(), errors.As(err, &sourceErr))
+	assert.Equal(suite.T(), source, sourceErr.Source)
+	assert.Equal(suite.T(), key, sourceErr.Key)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromEnvSuccess() {
+	suite.setEnv(map[string]string{
+		"TEST_DUSUPAY_SANDBOX":                 "true",
+		"TEST_DUSUPAY_PUBLIC_KEY":              "PublicKey",
+		"TEST_DUSUPAY_SECRET_KEY":              "SecretKey",
+		"TEST_DUSUPAY_WEBHOOK_HASH":            "WebhookHash",
+		"TEST_DUSUPAY_WEBHOOK_PUBLIC_KEY_PATH": "stubs/rsa/public-key.pem",
+		"TEST_DUSUPAY_TIMEOUT":                 "15s",
+		"TEST_DUSUPAY_MAX_RETRIES":             "2",
+		"TEST_DUSUPAY_RETRY_DELAY":             "3",
+	})
+	result, err := LoadConfigFromEnv("TEST_DUSUPAY")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SandboxAPIUrl, result.Uri)
+	assert.Equal(suite.T(), "PublicKey", result.PublicKey)
+	assert.Equal(suite.T(), "SecretKey", result.SecretKey)
+	assert.Equal(suite.T(), "WebhookHash", result.WebhookHash)
+	assert.Equal(suite.T(), "stubs/rsa/public-key.pem", result.WebhookPublicKeyPath)
+	assert.Equal(suite.T(), 15*time.Second, result.Timeout)
+	assert.Equal(suite.T(), 2, result.MaxRetries)
+	assert.Equal(suite.T(), 3*time.Second, result.RetryDelay)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromEnvDefaultProd() {
+	suite.setEnv(map[string]string{"DUSUPAY_PUBLIC_KEY": "PublicKey", "DUSUPAY_SECRET_KEY": "SecretKey"})
+	result, err := LoadConfigFromEnv("")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ProdAPIUrl, result.Uri)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromEnvMissingSecretKey() {
+	suite.setEnv(map[string]string{"TEST_DUSUPAY_PUBLIC_KEY": "PublicKey"})
+	result, err := LoadConfigFromEnv("TEST_DUSUPAY")
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_SECRET_KEY")
+	assert.Equal(suite.T(), `config source "env" key "TEST_DUSUPAY_SECRET_KEY" error: parameter "secret_key" is empty`, err.Error())
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromEnvWrongValues() {
+	suite.setEnv(map[string]string{"TEST_DUSUPAY_PUBLIC_KEY": "PublicKey", "TEST_DUSUPAY_SECRET_KEY": "SecretKey", "TEST_DUSUPAY_TIMEOUT": "soon"})
+	_, err := LoadConfigFromEnv("TEST_DUSUPAY")
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_TIMEOUT")
+
+	suite.setEnv(map[string]string{"TEST_DUSUPAY_TIMEOUT": "-1s"})
+	_, err = LoadConfigFromEnv("TEST_DUSUPAY")
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_TIMEOUT")
+	assert.Equal(suite.T(), `config source "env" key "TEST_DUSUPAY_TIMEOUT" error: parameter "timeout" is negative`, err.Error())
+
+	suite.setEnv(map[string]string{"TEST_DUSUPAY_TIMEOUT": "", "TEST_DUSUPAY_SANDBOX": "maybe"})
+	_, err = LoadConfigFromEnv("TEST_DUSUPAY")
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_SANDBOX")
+
+	suite.setEnv(map[string]string{"TEST_DUSUPAY_SANDBOX": "", "TEST_DUSUPAY_WEBHOOK_PUBLIC_KEY_PATH": "stubs/rsa/missing.pem"})
+	_, err = LoadConfigFromEnv("TEST_DUSUPAY")
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_WEBHOOK_PUBLIC_KEY_PATH")
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromJsonFile() {
+	result, err := LoadConfigFromFile("stubs/config/config.json")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SandboxAPIUrl, result.Uri)
+	assert.Equal(suite.T(), "PublicKey", result.PublicKey)
+	assert.Equal(suite.T(), "SecretKey", result.SecretKey)
+	assert.Equal(suite.T(), "WebhookHash", result.WebhookHash)
+	assert.Equal(suite.T(), 30*time.Second, result.Timeout)
+	assert.Equal(suite.T(), 2, result.MaxRetries)
+	assert.Equal(suite.T(), time.Second, result.RetryDelay)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromYamlFile() {
+	result, err := LoadConfigFromFile("stubs/config/config.yaml")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ProdAPIUrl, result.Uri)
+	assert.Equal(suite.T(), "stubs/rsa/public-key.pem", result.WebhookPublicKeyPath)
+	assert.Equal(suite.T(), time.Minute, result.Timeout)
+	assert.Equal(suite.T(), 3, result.MaxRetries)
+	assert.Equal(suite.T(), 500*time.Millisecond, result.RetryDelay)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromFileErrors() {
+	_, err := LoadConfigFromFile("stubs/config/invalid.yaml")
+	suite.assertSourceError(err, "file:stubs/config/invalid.yaml", "secret_key")
+	assert.Equal(suite.T(), `config source "file:stubs/config/invalid.yaml" key "secret_key" error: parameter "secret_key" is empty`, err.Error())
+
+	_, err = LoadConfigFromFile("stubs/config/missing.json")
+	suite.assertSourceError(err, "file:stubs/config/missing.json", "")
+
+	_, err = LoadConfigFromFile("stubs/rsa/public-key.pem")
+	assert.Equal(suite.T(), `config source "file:stubs/rsa/public-key.pem" error: unsupported config file extension ".pem"`, err.Error())
+
+	_, err = LoadConfigFromJSON([]byte(`{"public_key": 1}`))
+	suite.assertSourceError(err, "json", "")
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigFromYaml() {
+	result, err := LoadConfigFromYAML([]byte("public_key: PublicKey\nsecret_key: SecretKey\nsandbox: yes\n"))
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "yaml", "sandbox")
+
+	result, err = LoadConfigFromYAML([]byte("public_key: PublicKey\nsecret_key: SecretKey\nsandbox: true\n"))
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.IsSandbox())
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigEnvironment() {
+	result, err := LoadConfigFromYAML([]byte("environment: Sandbox\npublic_key: PublicKey\nsecret_key: SecretKey\n"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), EnvironmentSandbox, result.Environment)
+	assert.Equal(suite.T(), SandboxAPIUrl, result.Uri)
+
+	result, err = LoadConfigFromYAML([]byte("environment: staging\npublic_key: PublicKey\nsecret_key: SecretKey\n"))
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "yaml", "environment")
+
+	suite.setEnv(map[string]string{
+		"TEST_DUSUPAY_ENVIRONMENT": "production",
+		"TEST_DUSUPAY_URI":         SandboxAPIUrl,
+		"TEST_DUSUPAY_PUBLIC_KEY":  "PublicKey",
+		"TEST_DUSUPAY_SECRET_KEY":  "SecretKey",
+	})
+	result, err = LoadConfigFromEnv("TEST_DUSUPAY")
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_URI")
+	assert.Equal(suite.T(), `config source "env" key "TEST_DUSUPAY_URI" error: parameter "uri" does not match production environment`, err.Error())
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigCustomEnvironmentWithoutUri() {
+	suite.setEnv(map[string]string{
+		"TEST_DUSUPAY_ENVIRONMENT": "custom",
+		"TEST_DUSUPAY_PUBLIC_KEY":  "PublicKey",
+		"TEST_DUSUPAY_SECRET_KEY":  "SecretKey",
+	})
+	result, err := LoadConfigFromEnv("TEST_DUSUPAY")
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_URI")
+	assert.Equal(suite.T(), `config source "env" key "TEST_DUSUPAY_URI" error: parameter "uri" is empty for custom environment`, err.Error())
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigEnvironmentConflictsWithSandbox() {
+	suite.setEnv(map[string]string{
+		"TEST_DUSUPAY_ENVIRONMENT": "production",
+		"TEST_DUSUPAY_SANDBOX":     "true",
+		"TEST_DUSUPAY_PUBLIC_KEY":  "PublicKey",
+		"TEST_DUSUPAY_SECRET_KEY":  "SecretKey",
+	})
+	result, err := LoadConfigFromEnv("TEST_DUSUPAY")
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "env", "TEST_DUSUPAY_SANDBOX")
+	assert.Equal(suite.T(), `config source "env" key "TEST_DUSUPAY_SANDBOX" error: parameter "sandbox" conflicts with production environment`, err.Error())
+
+	result, err = LoadConfigFromYAML([]byte("environment: sandbox\nsandbox: false\npublic_key: PublicKey\nsecret_key: SecretKey\n"))
+	assert.Nil(suite.T(), result)
+	suite.assertSourceError(err, "yaml", "sandbox")
+
+	result, err = LoadConfigFromYAML([]byte("environment: sandbox\nsandbox: true\npublic_key: PublicKey\nsecret_key: SecretKey\n"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SandboxAPIUrl, result.Uri)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigSecrets() {
+	provider := StaticSecretProvider{"dusupay/public": "PublicKey", "dusupay/secret": "SecretKey", "dusupay/hash": "WebhookHash"}
+	cfg := &Config{Uri: SandboxAPIUrl}
+	err := LoadConfigSecrets(context.Background(), cfg, provider, &ConfigSecretNames{PublicKey: "dusupay/public", SecretKey: "dusupay/secret", WebhookHash: "dusupay/hash"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "PublicKey", cfg.PublicKey)
+	assert.Equal(suite.T(), "SecretKey", cfg.SecretKey)
+	assert.Equal(suite.T(), "WebhookHash", cfg.WebhookHash)
+}
+
+func (suite *ConfigLoaderTestSuite) TestLoadConfigSecretsErrors() {
+	provider := StaticSecretProvider{"dusupay/public": "PublicKey", "dusupay/secret": ""}
+	err := LoadConfigSecrets(context.Background(), &Config{Uri: SandboxAPIUrl}, provider, &ConfigSecretNames{PublicKey: "dusupay/public", SecretKey: "dusupay/unknown"})
+	suite.assertSourceError(err, "secrets", "dusupay/unknown")
+	assert.Equal(suite.T(), `config source "secrets" key "dusupay/unknown" error: secret "dusupay/unknown" not found`, err.Error())
+
+	err = LoadConfigSecrets(context.Background(), &Config{Uri: SandboxAPIUrl}, provider, &ConfigSecretNames{PublicKey: "dusupay/public", SecretKey: "dusupay/secret"})
+	suite.assertSourceError(err, "secrets", "dusupay/secret")
+}
+
+func TestConfigLoaderTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigLoaderTestSuite))
+}
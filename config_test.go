@@ -30,10 +30,78 @@ func (suite *ConfigTestSuite) TestNewConfigSandbox() {
 
 func (suite *ConfigTestSuite) TestIsSandbox() {
 	assert.False(suite.T(), suite.testable.IsSandbox())
+	assert.True(suite.T(), NewConfigSandbox("foo", "bar").IsSandbox())
+	//without the environment it is detected by the uri
+	suite.testable.Environment = ""
 	suite.testable.Uri = SandboxAPIUrl
 	assert.True(suite.T(), suite.testable.IsSandbox())
 }
 
+func (suite *ConfigTestSuite) TestIsSandboxCustomUri() {
+	suite.testable.Environment = ""
+	suite.testable.Uri = "https://api.dusupay.co"
+	assert.False(suite.T(), suite.testable.IsSandbox())
+	assert.False(suite.T(), suite.testable.IsProduction())
+	assert.Equal(suite.T(), EnvironmentCustom, suite.testable.GetEnvironment())
+}
+
+func (suite *ConfigTestSuite) TestGetEnvironment() {
+	assert.Equal(suite.T(), EnvironmentProduction, suite.testable.Environment)
+	assert.Equal(suite.T(), EnvironmentProduction, suite.testable.GetEnvironment())
+	assert.True(suite.T(), suite.testable.IsProduction())
+	suite.testable.Environment = ""
+	suite.testable.Uri = SandboxAPIUrl + "/"
+	assert.Equal(suite.T(), EnvironmentSandbox, suite.testable.GetEnvironment())
+	suite.testable.Environment = EnvironmentCustom
+	assert.Equal(suite.T(), EnvironmentCustom, suite.testable.GetEnvironment())
+}
+
+func (suite *ConfigTestSuite) TestNewConfigForEnvironment() {
+	result := NewConfigForEnvironment(EnvironmentSandbox, "foo", "bar")
+	assert.Equal(suite.T(), EnvironmentSandbox, result.Environment)
+	assert.Equal(suite.T(), SandboxAPIUrl, result.Uri)
+	assert.True(suite.T(), result.IsSandbox())
+	assert.NoError(suite.T(), result.IsValid())
+}
+
+func (suite *ConfigTestSuite) TestNewConfigCustom() {
+	result := NewConfigCustom("http://localhost:8080", "foo", "bar")
+	assert.Equal(suite.T(), EnvironmentCustom, result.Environment)
+	assert.Equal(suite.T(), "http://localhost:8080", result.Uri)
+	assert.False(suite.T(), result.IsSandbox())
+	assert.NoError(suite.T(), result.IsValid())
+}
+
+func (suite *ConfigTestSuite) TestIsValidEnvironmentUriMismatch() {
+	suite.testable.Environment = EnvironmentSandbox
+	result := suite.testable.IsValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "uri" does not match sandbox environment`, result.Error())
+}
+
+func (suite *ConfigTestSuite) TestIsValidSandboxConfigWithProductionUri() {
+	cfg := NewConfigSandbox("foo", "bar")
+	assert.Equal(suite.T(), EnvironmentSandbox, cfg.Environment)
+	cfg.Uri = ProdAPIUrl
+	result := cfg.IsValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "uri" does not match sandbox environment`, result.Error())
+}
+
+func (suite *ConfigTestSuite) TestIsValidProductionConfigWithSandboxUri() {
+	suite.testable.Uri = SandboxAPIUrl
+	result := suite.testable.IsValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "uri" does not match production environment`, result.Error())
+}
+
+func (suite *ConfigTestSuite) TestIsValidInvalidEnvironment() {
+	suite.testable.Environment = "staging"
+	result := suite.testable.IsValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "environment" is invalid`, result.Error())
+}
+
 func (suite *ConfigTestSuite) TestIsValidSuccess() {
 	assert.Nil(suite.T(), suite.testable.IsValid())
 	assert.NoError(suite.T(), suite.testable.IsValid())
@@ -60,6 +128,28 @@ func (suite *ConfigTestSuite) TestIsValidEmptySecretKey() {
 	assert.Equal(suite.T(), `parameter "secret_key" is empty`, result.Error())
 }
 
+func (suite *ConfigTestSuite) TestIsValidNegativeTimeout() {
+	suite.testable.Timeout = -1
+	result := suite.testable.IsValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "timeout" is negative`, result.Error())
+}
+
+func (suite *ConfigTestSuite) TestIsValidNegativeMaxRetries() {
+	suite.testable.MaxRetries = -1
+	result := suite.testable.IsValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "max_retries" is negative`, result.Error())
+}
+
+func (suite *ConfigTestSuite) TestIsValidWithCredentialsProvider() {
+	suite.testable.PublicKey = ""
+	suite.testable.SecretKey = ""
+	suite.testable.CredentialsProvider, _ = NewRotatingCredentialsProvider("PublicKey", "SecretKey")
+	result := suite.testable.IsValid()
+	assert.NoError(suite.T(), result)
+}
+
 func TestConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(ConfigTestSuite))
 }
@@ -0,0 +1,240 @@
+package dusupay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Credentials merchant API keys pair
+type Credentials struct {
+	PublicKey string `json:"public_key" yaml:"public_key"`
+	SecretKey string `json:"secret_key" yaml:"secret_key"`
+}
+
+//Check is valid Credentials parameters
+func (c *Credentials) isValid() error {
+	var err error
+	if c.PublicKey == "" {
+		err = fmt.Errorf(`parameter "public_key" is empty`)
+	} else if c.SecretKey == "" {
+		err = fmt.Errorf(`parameter "secret_key" is empty`)
+	}
+	return err
+}
+
+//CredentialsProviderInterface credentials source consulted per request
+type CredentialsProviderInterface interface {
+	GetCredentials(ctx context.Context) (*Credentials, error)
+}
+
+//NewRotatingCredentialsProvider create new hot-swappable credentials provider
+func NewRotatingCredentialsProvider(publicKey string, secretKey string) (*RotatingCredentialsProvider, error) {
+	cp := &RotatingCredentialsProvider{}
+	err := cp.Rotate(publicKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+//RotatingCredentialsProvider credentials provider with safe runtime keys rotation
+type RotatingCredentialsProvider struct {
+	mu          sync.RWMutex
+	credentials Credentials
+}
+
+//GetCredentials get current credentials copy
+func (cp *RotatingCredentialsProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	credentials := cp.credentials
+	return &credentials, nil
+}
+
+//Rotate replace credentials (in-flight requests keep the previous keys pair)
+func (cp *RotatingCredentialsProvider) Rotate(publicKey string, secretKey string) error {
+	credentials := Credentials{PublicKey: publicKey, SecretKey: secretKey}
+	err := credentials.isValid()
+	if err != nil {
+		return fmt.Errorf("RotatingCredentialsProvider.Rotate error: %v", err)
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.credentials = credentials
+	return nil
+}
+
+//NewFileCredentialsProvider create new credentials provider backed by the JSON or YAML file (public_key, secret_key)
+func NewFileCredentialsProvider(path string) (*FileCredentialsProvider, error) {
+	fp := &FileCredentialsProvider{path: path}
+	_, err := fp.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return fp, nil
+}
+
+//FileCredentialsProvider credentials provider reloading the keys from the file
+type FileCredentialsProvider struct {
+	RotatingCredentialsProvider
+	path    string
+	mu      sync.Mutex
+	content []byte
+}
+
+//Reload read credentials file and rotate the keys when the content is changed
+func (fp *FileCredentialsProvider) Reload() (bool, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	data, err := ioutil.ReadFile(fp.path)
+	if err != nil {
+		return false, fmt.Errorf("FileCredentialsProvider.Reload error: %v", err)
+	}
+	if fp.content != nil && bytes.Equal(data, fp.content) {
+		return false, nil
+	}
+	var credentials Credentials
+	switch strings.ToLower(filepath.Ext(fp.path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &credentials)
+	default:
+		err = json.Unmarshal(data, &credentials)
+	}
+	if err != nil {
+		return false, fmt.Errorf("FileCredentialsProvider.Reload error: %v", err)
+	}
+	err = fp.Rotate(credentials.PublicKey, credentials.SecretKey)
+	if err != nil {
+		return false, fmt.Errorf("FileCredentialsProvider.Reload error: %v", err)
+	}
+	fp.content = data
+	return true, nil
+}
+
+//Watch reload credentials file on the interval until the context is done (previous keys are kept on reload errors)
+func (fp *FileCredentialsProvider) Watch(ctx context.Context, interval time.Duration, onError func(err error)) error {
+	if interval <= 0 {
+		return fmt.Errorf(`FileCredentialsProvider.Watch error: parameter "interval" must be positive`)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := fp.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+//webhookKey webhook signature public key
+type webhookKey struct {
+	validator *SignatureValidator
+	//zero value means the key does not expire
+	validUntil time.Time
+}
+
+//NewMultiKeySignatureValidator create new webhook signature validator accepting several public keys
+func NewMultiKeySignatureValidator() *MultiKeySignatureValidator {
+	return &MultiKeySignatureValidator{Now: time.Now, keys: make(map[string]*webhookKey)}
+}
+
+//MultiKeySignatureValidator webhook signature validator with the keys rotation overlap windows
+type MultiKeySignatureValidator struct {
+	Now  func() time.Time
+	mu   sync.RWMutex
+	keys map[string]*webhookKey
+}
+
+//AddKey add PEM public key (zero validUntil means the key does not expire)
+func (mv *MultiKeySignatureValidator) AddKey(id string, publicKeyBytes []byte, validUntil time.Time) error {
+	if id == "" {
+		return fmt.Errorf(`MultiKeySignatureValidator.AddKey error: parameter "id" is empty`)
+	}
+	validator, err := NewSignatureValidator(publicKeyBytes)
+	if err != nil {
+		return fmt.Errorf(`MultiKeySignatureValidator.AddKey error: key "%s": %v`, id, err)
+	}
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+	mv.keys[id] = &webhookKey{validator: validator, validUntil: validUntil}
+	return nil
+}
+
+//RemoveKey remove public key
+func (mv *MultiKeySignatureValidator) RemoveKey(id string) {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+	delete(mv.keys, id)
+}
+
+//Rotate add new public key, the current keys are still accepted during the overlap window
+func (mv *MultiKeySignatureValidator) Rotate(id string, publicKeyBytes []byte, overlap time.Duration) error {
+	validator, err := NewSignatureValidator(publicKeyBytes)
+	if err != nil {
+		return fmt.Errorf(`MultiKeySignatureValidator.Rotate error: key "%s": %v`, id, err)
+	}
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+	expireAt := mv.Now().Add(overlap)
+	for _, key := range mv.keys {
+		if key.validUntil.IsZero() || key.validUntil.After(expireAt) {
+			key.validUntil = expireAt
+		}
+	}
+	mv.keys[id] = &webhookKey{validator: validator}
+	return nil
+}
+
+//KeyIDs get ids of the currently accepted keys (sorted)
+func (mv *MultiKeySignatureValidator) KeyIDs() []string {
+	mv.mu.RLock()
+	defer mv.mu.RUnlock()
+	now := mv.Now()
+	var ids []string
+	for id, key := range mv.keys {
+		if key.isActive(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+//ValidateSignature validate webhook signature against any of the currently accepted keys
+func (mv *MultiKeySignatureValidator) ValidateSignature(webhook IncomingWebhookInterface, webhookUrl string, signature string) error {
+	mv.mu.RLock()
+	defer mv.mu.RUnlock()
+	now := mv.Now()
+	var lastErr error
+	for _, key := range mv.keys {
+		if !key.isActive(now) {
+			continue
+		}
+		lastErr = key.validator.ValidateSignature(webhook, webhookUrl, signature)
+		if lastErr == nil {
+			return nil
+		}
+	}
+	if lastErr == nil {
+		return fmt.Errorf("MultiKeySignatureValidator.ValidateSignature error: no active public keys")
+	}
+	return fmt.Errorf("MultiKeySignatureValidator.ValidateSignature error: %v", lastErr)
+}
+
+//isActive check is key accepted at the moment
+func (wk *webhookKey) isActive(now time.Time) bool {
+	return wk.validUntil.IsZero() || now.Before(wk.validUntil)
+}
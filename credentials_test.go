@@ -0,0 +1,242 @@
+package dusupay
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stubCredentialsProvider struct {
+	credentials *Credentials
+	err         error
+}
+
+func (p *stubCredentialsProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	return p.credentials, p.err
+}
+
+func buildStubRSAKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyBytes, _ := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	return privateKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})
+}
+
+func signStubWebhook(privateKey *rsa.PrivateKey, webhook IncomingWebhookInterface, url string) string {
+	digest := sha512.Sum512([]byte(webhook.BuildPayloadString(url)))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA512, digest[:])
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+type CredentialsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	dir string
+}
+
+func (suite *CredentialsTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.dir, _ = ioutil.TempDir("", "dusupay-credentials")
+}
+
+func (suite *CredentialsTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *CredentialsTestSuite) writeFile(name string, content string) string {
+	path := filepath.Join(suite.dir, name)
+	_ = ioutil.WriteFile(path, []byte(content), 0600)
+	return path
+}
+
+func (suite *CredentialsTestSuite) TestRotatingCredentialsProviderRotate() {
+	provider, err := NewRotatingCredentialsProvider("PublicKey", "SecretKey")
+	assert.NoError(suite.T(), err)
+	before, _ := provider.GetCredentials(suite.ctx)
+
+	err = provider.Rotate("NewPublicKey", "NewSecretKey")
+	after, _ := provider.GetCredentials(suite.ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &Credentials{PublicKey: "PublicKey", SecretKey: "SecretKey"}, before)
+	assert.Equal(suite.T(), &Credentials{PublicKey: "NewPublicKey", SecretKey: "NewSecretKey"}, after)
+}
+
+func (suite *CredentialsTestSuite) TestRotatingCredentialsProviderRotateInvalid() {
+	provider, _ := NewRotatingCredentialsProvider("PublicKey", "SecretKey")
+
+	err := provider.Rotate("NewPublicKey", "")
+	result, _ := provider.GetCredentials(suite.ctx)
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RotatingCredentialsProvider.Rotate error: parameter "secret_key" is empty`, err.Error())
+	assert.Equal(suite.T(), "SecretKey", result.SecretKey)
+}
+
+func (suite *CredentialsTestSuite) TestNewRotatingCredentialsProviderInvalid() {
+	provider, err := NewRotatingCredentialsProvider("", "SecretKey")
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), provider)
+	assert.Equal(suite.T(), `RotatingCredentialsProvider.Rotate error: parameter "public_key" is empty`, err.Error())
+}
+
+func (suite *CredentialsTestSuite) TestRotatingCredentialsProviderConcurrentRotate() {
+	provider, _ := NewRotatingCredentialsProvider("PublicKey-0", "SecretKey-0")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = provider.Rotate("PublicKey-1", "SecretKey-1")
+		}()
+		go func() {
+			defer wg.Done()
+			credentials, _ := provider.GetCredentials(suite.ctx)
+			assert.Equal(suite.T(), credentials.PublicKey[len(credentials.PublicKey)-1], credentials.SecretKey[len(credentials.SecretKey)-1])
+		}()
+	}
+	wg.Wait()
+}
+
+func (suite *CredentialsTestSuite) TestFileCredentialsProviderReload() {
+	path := suite.writeFile("credentials.json", `{"public_key":"PublicKey","secret_key":"SecretKey"}`)
+	provider, err := NewFileCredentialsProvider(path)
+	assert.NoError(suite.T(), err)
+
+	changed, err := provider.Reload()
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), changed)
+
+	suite.writeFile("credentials.json", `{"public_key":"NewPublicKey","secret_key":"NewSecretKey"}`)
+	changed, err = provider.Reload()
+	result, _ := provider.GetCredentials(suite.ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), changed)
+	assert.Equal(suite.T(), &Credentials{PublicKey: "NewPublicKey", SecretKey: "NewSecretKey"}, result)
+}
+
+func (suite *CredentialsTestSuite) TestFileCredentialsProviderYAML() {
+	path := suite.writeFile("credentials.yaml", "public_key: PublicKey\nsecret_key: SecretKey\n")
+	provider, err := NewFileCredentialsProvider(path)
+	result, _ := provider.GetCredentials(suite.ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &Credentials{PublicKey: "PublicKey", SecretKey: "SecretKey"}, result)
+}
+
+func (suite *CredentialsTestSuite) TestFileCredentialsProviderReloadKeepsPreviousOnError() {
+	path := suite.writeFile("credentials.json", `{"public_key":"PublicKey","secret_key":"SecretKey"}`)
+	provider, _ := NewFileCredentialsProvider(path)
+
+	suite.writeFile("credentials.json", `{"public_key":"NewPublicKey"}`)
+	changed, err := provider.Reload()
+	result, _ := provider.GetCredentials(suite.ctx)
+
+	assert.Error(suite.T(), err)
+	assert.False(suite.T(), changed)
+	assert.Equal(suite.T(), `FileCredentialsProvider.Reload error: RotatingCredentialsProvider.Rotate error: parameter "secret_key" is empty`, err.Error())
+	assert.Equal(suite.T(), "PublicKey", result.PublicKey)
+}
+
+func (suite *CredentialsTestSuite) TestNewFileCredentialsProviderNotFound() {
+	provider, err := NewFileCredentialsProvider(filepath.Join(suite.dir, "missing.json"))
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), provider)
+	assert.Contains(suite.T(), err.Error(), "FileCredentialsProvider.Reload error:")
+}
+
+func (suite *CredentialsTestSuite) TestFileCredentialsProviderWatch() {
+	path := suite.writeFile("credentials.json", `{"public_key":"PublicKey","secret_key":"SecretKey"}`)
+	provider, _ := NewFileCredentialsProvider(path)
+	ctx, cancel := context.WithCancel(suite.ctx)
+	done := make(chan error)
+	go func() {
+		done <- provider.Watch(ctx, 5*time.Millisecond, nil)
+	}()
+
+	suite.writeFile("credentials.json", `{"public_key":"NewPublicKey","secret_key":"NewSecretKey"}`)
+	assert.Eventually(suite.T(), func() bool {
+		result, _ := provider.GetCredentials(suite.ctx)
+		return result.PublicKey == "NewPublicKey"
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.Equal(suite.T(), context.Canceled, <-done)
+}
+
+func (suite *CredentialsTestSuite) TestFileCredentialsProviderWatchInvalidInterval() {
+	path := suite.writeFile("credentials.json", `{"public_key":"PublicKey","secret_key":"SecretKey"}`)
+	provider, _ := NewFileCredentialsProvider(path)
+	err := provider.Watch(suite.ctx, 0, nil)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `FileCredentialsProvider.Watch error: parameter "interval" must be positive`, err.Error())
+}
+
+func (suite *CredentialsTestSuite) TestMultiKeySignatureValidatorRotateOverlap() {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldKeyBytes, _ := ioutil.ReadFile("stubs/rsa/public-key.pem")
+	newPrivateKey, newKeyBytes := buildStubRSAKey(suite.T())
+	webhook := &CollectionWebhook{ID: 226, InternalReference: "DUSUPAY405GZM1G5JXGA71IK", TransactionStatus: "COMPLETED"}
+	url := "https://www.sample-url.com/callback"
+	validator := NewMultiKeySignatureValidator()
+	validator.Now = func() time.Time { return now }
+	_ = validator.AddKey("2020", oldKeyBytes, time.Time{})
+
+	err := validator.Rotate("2021", newKeyBytes, time.Hour)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"2020", "2021"}, validator.KeyIDs())
+	assert.NoError(suite.T(), validator.ValidateSignature(webhook, url, stubSignature))
+	assert.NoError(suite.T(), validator.ValidateSignature(webhook, url, signStubWebhook(newPrivateKey, webhook, url)))
+
+	now = now.Add(time.Hour)
+	assert.Equal(suite.T(), []string{"2021"}, validator.KeyIDs())
+	err = validator.ValidateSignature(webhook, url, stubSignature)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "MultiKeySignatureValidator.ValidateSignature error: crypto/rsa: verification error", err.Error())
+	assert.NoError(suite.T(), validator.ValidateSignature(webhook, url, signStubWebhook(newPrivateKey, webhook, url)))
+}
+
+func (suite *CredentialsTestSuite) TestMultiKeySignatureValidatorNoKeys() {
+	validator := NewMultiKeySignatureValidator()
+	err := validator.ValidateSignature(&CollectionWebhook{}, "https://www.sample-url.com/callback", stubSignature)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "MultiKeySignatureValidator.ValidateSignature error: no active public keys", err.Error())
+}
+
+func (suite *CredentialsTestSuite) TestMultiKeySignatureValidatorRemoveKey() {
+	keyBytes, _ := ioutil.ReadFile("stubs/rsa/public-key.pem")
+	validator := NewMultiKeySignatureValidator()
+	_ = validator.AddKey("2020", keyBytes, time.Time{})
+	validator.RemoveKey("2020")
+	assert.Empty(suite.T(), validator.KeyIDs())
+}
+
+func (suite *CredentialsTestSuite) TestMultiKeySignatureValidatorAddKeyInvalid() {
+	validator := NewMultiKeySignatureValidator()
+	err := validator.AddKey("2020", []byte(`foo`), time.Time{})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `MultiKeySignatureValidator.AddKey error: key "2020": wrong public key data`, err.Error())
+
+	err = validator.AddKey("", []byte(`foo`), time.Time{})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `MultiKeySignatureValidator.AddKey error: parameter "id" is empty`, err.Error())
+}
+
+func TestCredentialsTestSuite(t *testing.T) {
+	suite.Run(t, new(CredentialsTestSuite))
+}
@@ -0,0 +1,124 @@
+package dusupay
+
+import (
+	"regexp"
+)
+
+//CryptoAssetCode type
+type CryptoAssetCode string
+
+//CryptoAssetBTC const
+const CryptoAssetBTC CryptoAssetCode = "BTC"
+
+//CryptoAssetETH const
+const CryptoAssetETH CryptoAssetCode = "ETH"
+
+//CryptoAssetUSDT const
+const CryptoAssetUSDT CryptoAssetCode = "USDT"
+
+//CryptoAssetUSDC const
+const CryptoAssetUSDC CryptoAssetCode = "USDC"
+
+//CryptoNetworkCode type
+type CryptoNetworkCode string
+
+//CryptoNetworkBitcoin const
+const CryptoNetworkBitcoin CryptoNetworkCode = "BITCOIN"
+
+//CryptoNetworkEthereum const (ERC20)
+const CryptoNetworkEthereum CryptoNetworkCode = "ETHEREUM"
+
+//CryptoNetworkTron const (TRC20)
+const CryptoNetworkTron CryptoNetworkCode = "TRON"
+
+//CryptoNetworkBSC const (BEP20)
+const CryptoNetworkBSC CryptoNetworkCode = "BSC"
+
+//cryptoNetwork network assets and wallet address format
+type cryptoNetwork struct {
+	assets  []CryptoAssetCode
+	address *regexp.Regexp
+}
+
+//cryptoNetworks supported crypto networks
+var cryptoNetworks = map[CryptoNetworkCode]*cryptoNetwork{
+	CryptoNetworkBitcoin: {
+		assets:  []CryptoAssetCode{CryptoAssetBTC},
+		address: regexp.MustCompile(`^(bc1[a-z0-9]{25,87}|[13][a-km-zA-HJ-NP-Z1-9]{25,34})$`),
+	},
+	CryptoNetworkEthereum: {
+		assets:  []CryptoAssetCode{CryptoAssetETH, CryptoAssetUSDT, CryptoAssetUSDC},
+		address: regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`),
+	},
+	CryptoNetworkTron: {
+		assets:  []CryptoAssetCode{CryptoAssetUSDT},
+		address: regexp.MustCompile(`^T[1-9A-HJ-NP-Za-km-z]{33}$`),
+	},
+	CryptoNetworkBSC: {
+		assets:  []CryptoAssetCode{CryptoAssetUSDT, CryptoAssetUSDC},
+		address: regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`),
+	},
+}
+
+//CryptoParams crypto transaction request parameters
+type CryptoParams struct {
+	Asset   CryptoAssetCode   `json:"asset"`
+	Network CryptoNetworkCode `json:"network"`
+}
+
+//isValid check is valid CryptoParams parameters
+func (cp *CryptoParams) isValid() error {
+	return cp.Validate().first()
+}
+
+//Validate collect all CryptoParams parameters errors (nil when the parameters are valid)
+func (cp *CryptoParams) Validate() ValidationErrors {
+	var errs ValidationErrors
+	if cp == nil {
+		errs.addIf(true, "crypto", ValidationReasonMissing, "is empty")
+		return errs
+	}
+	errs.addIf(cp.Asset == "", "crypto.asset", ValidationReasonMissing, "is empty")
+	errs.addIf(cp.Network == "", "crypto.network", ValidationReasonMissing, "is empty")
+	if cp.Network == "" {
+		return errs
+	}
+	network, ok := cryptoNetworks[cp.Network]
+	errs.addIf(!ok, "crypto.network", ValidationReasonInvalidEnum, `value "%s" is not supported`, cp.Network)
+	errs.addIf(ok && cp.Asset != "" && !network.hasAsset(cp.Asset), "crypto.asset", ValidationReasonInvalidEnum, `value "%s" is not supported by network "%s"`, cp.Asset, cp.Network)
+	return errs
+}
+
+//hasAsset method
+func (cn *cryptoNetwork) hasAsset(asset CryptoAssetCode) bool {
+	for _, a := range cn.assets {
+		if a == asset {
+			return true
+		}
+	}
+	return false
+}
+
+//IsValidCryptoWalletAddress check is wallet address has valid format for the network
+func IsValidCryptoWalletAddress(network CryptoNetworkCode, address string) bool {
+	cn, ok := cryptoNetworks[network]
+	if !ok {
+		return false
+	}
+	return cn.address.MatchString(address)
+}
+
+//CryptoTransactionData crypto specific transaction data (responses and webhooks)
+type CryptoTransactionData struct {
+	Asset         string  `json:"asset"`
+	Network       string  `json:"network"`
+	WalletAddress string  `json:"wallet_address"`
+	Amount        float64 `json:"amount"`
+	TxHash        string  `json:"tx_hash"`
+	Confirmations int     `json:"confirmations"`
+}
+
+//IsConfirmed check is transaction has enough network confirmations
+func (ctd *CryptoTransactionData) IsConfirmed(required int) bool {
+	return ctd.TxHash != "" && ctd.Confirmations >= required
+}
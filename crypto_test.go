@@ -0,0 +1,124 @@
+package dusupay
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CryptoTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CryptoTestSuite) buildPayoutRequest() *PayoutRequest {
+	return &PayoutRequest{
+		Currency:          CurrencyCodeUSD,
+		Amount:            50,
+		Method:            TransactionMethodCrypto,
+		ProviderId:        "usdt_tron",
+		MerchantReference: "merchant_reference",
+		Narration:         "narration",
+		AccountNumber:     "TN9RRaXkCFtTXRso2GdTZxSxxwufzxLQPP",
+		Crypto:            &CryptoParams{Asset: CryptoAssetUSDT, Network: CryptoNetworkTron},
+	}
+}
+
+func (suite *CryptoTestSuite) TestCryptoParamsIsValid() {
+	cases := map[string]*CryptoParams{
+		`parameter "crypto" is empty`:                                             nil,
+		`parameter "crypto.asset" is empty`:                                       {Network: CryptoNetworkTron},
+		`parameter "crypto.network" is empty`:                                     {Asset: CryptoAssetUSDT},
+		`parameter "crypto.network" value "FOO" is not supported`:                 {Asset: CryptoAssetUSDT, Network: "FOO"},
+		`parameter "crypto.asset" value "BTC" is not supported by network "TRON"`: {Asset: CryptoAssetBTC, Network: CryptoNetworkTron},
+	}
+	for message, params := range cases {
+		err := params.isValid()
+		assert.Error(suite.T(), err)
+		assert.Equal(suite.T(), message, err.Error())
+	}
+	assert.NoError(suite.T(), (&CryptoParams{Asset: CryptoAssetUSDC, Network: CryptoNetworkEthereum}).isValid())
+}
+
+func (suite *CryptoTestSuite) TestIsValidCryptoWalletAddress() {
+	assert.True(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkBitcoin, "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"))
+	assert.True(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkBitcoin, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"))
+	assert.True(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkEthereum, "0x71C7656EC7ab88b098defB751B7401B5f6d8976F"))
+	assert.True(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkBSC, "0x71C7656EC7ab88b098defB751B7401B5f6d8976F"))
+	assert.True(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkTron, "TN9RRaXkCFtTXRso2GdTZxSxxwufzxLQPP"))
+	assert.False(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkTron, "0x71C7656EC7ab88b098defB751B7401B5f6d8976F"))
+	assert.False(suite.T(), IsValidCryptoWalletAddress(CryptoNetworkEthereum, "0x71C7656EC7"))
+	assert.False(suite.T(), IsValidCryptoWalletAddress("FOO", "0x71C7656EC7ab88b098defB751B7401B5f6d8976F"))
+}
+
+func (suite *CryptoTestSuite) TestPayoutRequestIsValidSuccess() {
+	assert.NoError(suite.T(), suite.buildPayoutRequest().isValid())
+}
+
+func (suite *CryptoTestSuite) TestPayoutRequestIsValidEmptyCrypto() {
+	request := suite.buildPayoutRequest()
+	request.Crypto = nil
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "crypto" is empty`, result.Error())
+}
+
+func (suite *CryptoTestSuite) TestPayoutRequestIsValidWrongWalletAddress() {
+	request := suite.buildPayoutRequest()
+	request.AccountNumber = "256777111786"
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "account_number" is not valid "TRON" wallet address`, result.Error())
+}
+
+func (suite *CryptoTestSuite) TestPayoutRequestTransformToMap() {
+	result, err := transformStructToMap(suite.buildPayoutRequest())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]interface{}{"asset": "USDT", "network": "TRON"}, result["crypto"])
+	result, _ = transformStructToMap(&PayoutRequest{})
+	assert.NotContains(suite.T(), result, "crypto")
+}
+
+func (suite *CryptoTestSuite) TestCollectionRequestIsValid() {
+	request := &CollectionRequest{
+		Currency:          CurrencyCodeUSD,
+		Amount:            50,
+		Method:            TransactionMethodCrypto,
+		ProviderId:        "btc",
+		MerchantReference: "merchant_reference",
+		Narration:         "narration",
+		RedirectUrl:       "redirect_url",
+	}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "crypto" is empty`, result.Error())
+	request.Crypto = &CryptoParams{Asset: CryptoAssetBTC, Network: CryptoNetworkBitcoin}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *CryptoTestSuite) TestPayoutWebhookUnmarshal() {
+	var webhook PayoutWebhook
+	body, _ := LoadStubResponseData("stubs/webhooks/request/payout-crypto-success.json")
+	err := json.Unmarshal(body, &webhook)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(227), webhook.ID)
+	assert.Equal(suite.T(), "USDT", webhook.Crypto.Asset)
+	assert.Equal(suite.T(), "TRON", webhook.Crypto.Network)
+	assert.Equal(suite.T(), "TN9RRaXkCFtTXRso2GdTZxSxxwufzxLQPP", webhook.Crypto.WalletAddress)
+	assert.Equal(suite.T(), float64(50), webhook.Crypto.Amount)
+	assert.Equal(suite.T(), "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90", webhook.Crypto.TxHash)
+	assert.Equal(suite.T(), 21, webhook.Crypto.Confirmations)
+	assert.True(suite.T(), webhook.Crypto.IsConfirmed(20))
+	assert.False(suite.T(), webhook.Crypto.IsConfirmed(30))
+}
+
+func (suite *CryptoTestSuite) TestCollectionWebhookUnmarshalWithoutCrypto() {
+	var webhook CollectionWebhook
+	body, _ := LoadStubResponseData("stubs/webhooks/request/collection-success.json")
+	_ = json.Unmarshal(body, &webhook)
+	assert.Nil(suite.T(), webhook.Crypto)
+}
+
+func TestCryptoTestSuite(t *testing.T) {
+	suite.Run(t, new(CryptoTestSuite))
+}
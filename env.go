@@ -1,7 +1,48 @@
 package dusupay
 
+import "strings"
+
 //ProdAPIUrl production ENV API url
 const ProdAPIUrl = "https://api.dusupay.com"
 
 //SandboxAPIUrl sandbox ENV API url
 const SandboxAPIUrl = "https://sandbox.dusupay.com"
+
+//Environment API environment (selects the API url only, Dusupay webhook public keys are not bundled and are configured separately)
+type Environment string
+
+//EnvironmentProduction production environment
+const EnvironmentProduction Environment = "production"
+
+//EnvironmentSandbox sandbox environment
+const EnvironmentSandbox Environment = "sandbox"
+
+//EnvironmentCustom custom environment (mock servers, proxies etc.)
+const EnvironmentCustom Environment = "custom"
+
+//IsValid check is known environment
+func (e Environment) IsValid() bool {
+	return e == EnvironmentProduction || e == EnvironmentSandbox || e == EnvironmentCustom
+}
+
+//GetBaseUrl get environment API url (empty for the custom environment)
+func (e Environment) GetBaseUrl() string {
+	switch e {
+	case EnvironmentProduction:
+		return ProdAPIUrl
+	case EnvironmentSandbox:
+		return SandboxAPIUrl
+	}
+	return ""
+}
+
+//DetectEnvironment detect environment by the API url (unknown urls are custom)
+func DetectEnvironment(uri string) Environment {
+	switch strings.TrimRight(uri, "/") {
+	case ProdAPIUrl:
+		return EnvironmentProduction
+	case SandboxAPIUrl:
+		return EnvironmentSandbox
+	}
+	return EnvironmentCustom
+}
@@ -0,0 +1,246 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//FeeEstimateRequest prospective transaction fee estimation request
+type FeeEstimateRequest struct {
+	TransactionType TransactionTypeCode   `json:"transaction_type"`
+	Method          TransactionMethodCode `json:"method"`
+	ProviderId      string                `json:"provider_id"`
+	Currency        CurrencyCode          `json:"currency"`
+	Amount          float64               `json:"amount"`
+}
+
+//Check is valid FeeEstimateRequest parameters
+func (fer *FeeEstimateRequest) isValid() error {
+	var err error
+	if fer.TransactionType == "" {
+		err = fmt.Errorf(`parameter "transaction_type" is empty`)
+	} else if fer.Method == "" {
+		err = fmt.Errorf(`parameter "method" is empty`)
+	} else if fer.ProviderId == "" {
+		err = fmt.Errorf(`parameter "provider_id" is empty`)
+	} else if fer.Currency == "" {
+		err = fmt.Errorf(`parameter "currency" is empty`)
+	} else if fer.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	}
+	return err
+}
+
+//NewFeeEstimateRequestFromCollection create fee estimation request for collection request
+func NewFeeEstimateRequestFromCollection(req *CollectionRequest) *FeeEstimateRequest {
+	return &FeeEstimateRequest{
+		TransactionType: TransactionTypeCollection,
+		Method:          req.Method,
+		ProviderId:      req.ProviderId,
+		Currency:        req.Currency,
+		Amount:          req.Amount,
+	}
+}
+
+//NewFeeEstimateRequestFromPayout create fee estimation request for payout request
+func NewFeeEstimateRequestFromPayout(req *PayoutRequest) *FeeEstimateRequest {
+	return &FeeEstimateRequest{
+		TransactionType: TransactionTypePayout,
+		Method:          req.Method,
+		ProviderId:      req.ProviderId,
+		Currency:        req.Currency,
+		Amount:          req.Amount,
+	}
+}
+
+//FeeEstimate expected transaction fee
+type FeeEstimate struct {
+	TransactionType TransactionTypeCode `json:"transaction_type"`
+	Currency        CurrencyCode        `json:"currency"`
+	Amount          float64             `json:"amount"`
+	TransactionFee  float64             `json:"transaction_fee"`
+	//expected total_credit for collections and total_debit for payouts and refunds
+	Total float64 `json:"total"`
+}
+
+//newFeeEstimate calculate total amount by transaction type
+func newFeeEstimate(req *FeeEstimateRequest, fee float64) *FeeEstimate {
+	total := req.Amount + fee
+	if req.TransactionType == TransactionTypeCollection {
+		total = req.Amount - fee
+	}
+	return &FeeEstimate{
+		TransactionType: req.TransactionType,
+		Currency:        req.Currency,
+		Amount:          req.Amount,
+		TransactionFee:  roundAmount(fee),
+		Total:           roundAmount(total),
+	}
+}
+
+//FeeEstimatorInterface interface
+type FeeEstimatorInterface interface {
+	EstimateFee(ctx context.Context, req *FeeEstimateRequest) (*FeeEstimate, error)
+}
+
+//FeeEstimateResponse struct
+type FeeEstimateResponse struct {
+	ResponseBody
+	Data *FeeEstimateResponseData `json:"data,omitempty"`
+}
+
+//FeeEstimateResponseData struct
+type FeeEstimateResponseData struct {
+	TransactionType string  `json:"transaction_type"`
+	Method          string  `json:"method"`
+	ProviderID      string  `json:"provider_id"`
+	Currency        string  `json:"currency"`
+	Amount          float64 `json:"amount"`
+	TransactionFee  float64 `json:"transaction_fee"`
+	Total           float64 `json:"total"`
+}
+
+//FeesResource wrapper
+type FeesResource struct {
+	ResourceAbstract
+}
+
+//Estimate get transaction fee estimation (see https://docs.dusupay.com/appendix/transaction-fees)
+func (r *FeesResource) Estimate(ctx context.Context, req *FeeEstimateRequest) (*FeeEstimateResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("FeesResource.Estimate error: %v", err)
+	}
+	query, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("FeesResource.Estimate error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/fees/estimate", query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("FeesResource.Estimate error: %v", err)
+	}
+	var result FeeEstimateResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("FeesResource.Estimate error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//EstimateFee FeeEstimatorInterface implementation
+func (r *FeesResource) EstimateFee(ctx context.Context, req *FeeEstimateRequest) (*FeeEstimate, error) {
+	result, _, err := r.Estimate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("FeesResource.EstimateFee error: empty response data")
+	}
+	estimate := newFeeEstimate(req, result.Data.TransactionFee)
+	if result.Data.Total != 0 {
+		estimate.Total = result.Data.Total
+	}
+	return estimate, nil
+}
+
+//FeeRule local fee schedule rule (empty criteria match any value)
+type FeeRule struct {
+	TransactionType TransactionTypeCode   `json:"transaction_type"`
+	Method          TransactionMethodCode `json:"method"`
+	ProviderId      string                `json:"provider_id"`
+	Currency        CurrencyCode          `json:"currency"`
+	Fixed           float64               `json:"fixed"`
+	//percent of the transaction amount (1.5 means 1.5%)
+	Percent float64 `json:"percent"`
+	MinFee  float64 `json:"min_fee"`
+	MaxFee  float64 `json:"max_fee"`
+}
+
+//matches check is rule matches request and returns rule specificity
+func (fr *FeeRule) matches(req *FeeEstimateRequest) (int, bool) {
+	specificity := 0
+	criteria := []struct {
+		rule  string
+		value string
+	}{
+		{string(fr.TransactionType), string(req.TransactionType)},
+		{string(fr.Method), string(req.Method)},
+		{fr.ProviderId, req.ProviderId},
+		{string(fr.Currency), string(req.Currency)},
+	}
+	for _, c := range criteria {
+		if c.rule == "" {
+			continue
+		}
+		if c.rule != c.value {
+			return 0, false
+		}
+		specificity++
+	}
+	return specificity, true
+}
+
+//calculate rule fee for amount
+func (fr *FeeRule) calculate(amount float64) float64 {
+	fee := fr.Fixed + amount*fr.Percent/100
+	if fr.MinFee != 0 && fee < fr.MinFee {
+		fee = fr.MinFee
+	}
+	if fr.MaxFee != 0 && fee > fr.MaxFee {
+		fee = fr.MaxFee
+	}
+	return fee
+}
+
+//FeeSchedule configurable local fee schedule
+type FeeSchedule struct {
+	Rules []*FeeRule `json:"rules"`
+}
+
+//EstimateFee FeeEstimatorInterface implementation (the most specific matching rule is used)
+func (fs *FeeSchedule) EstimateFee(ctx context.Context, req *FeeEstimateRequest) (*FeeEstimate, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, fmt.Errorf("FeeSchedule.EstimateFee error: %v", err)
+	}
+	var rule *FeeRule
+	best := -1
+	for _, r := range fs.Rules {
+		if specificity, ok := r.matches(req); ok && specificity > best {
+			rule, best = r, specificity
+		}
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("FeeSchedule.EstimateFee error: no fee rule for %s %s %s %s", req.TransactionType, req.Method, req.ProviderId, req.Currency)
+	}
+	return newFeeEstimate(req, rule.calculate(req.Amount)), nil
+}
+
+//NewFallbackFeeEstimator create fee estimator which uses fallback estimator if primary one fails
+func NewFallbackFeeEstimator(primary FeeEstimatorInterface, fallback FeeEstimatorInterface) *FallbackFeeEstimator {
+	return &FallbackFeeEstimator{primary: primary, fallback: fallback}
+}
+
+//FallbackFeeEstimator API-backed estimation with local fee schedule fallback
+type FallbackFeeEstimator struct {
+	primary  FeeEstimatorInterface
+	fallback FeeEstimatorInterface
+}
+
+//EstimateFee FeeEstimatorInterface implementation
+func (ffe *FallbackFeeEstimator) EstimateFee(ctx context.Context, req *FeeEstimateRequest) (*FeeEstimate, error) {
+	estimate, err := ffe.primary.EstimateFee(ctx, req)
+	if err == nil {
+		return estimate, nil
+	}
+	estimate, fallbackErr := ffe.fallback.EstimateFee(ctx, req)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("FallbackFeeEstimator.EstimateFee error: %v; %v", err, fallbackErr)
+	}
+	return estimate, nil
+}
@@ -0,0 +1,221 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type FeesTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	schedule *FeeSchedule
+}
+
+func (suite *FeesTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.schedule = &FeeSchedule{Rules: []*FeeRule{
+		{Percent: 3},
+		{TransactionType: TransactionTypePayout, Currency: CurrencyCodeUGX, Fixed: 500, Percent: 1, MinFee: 1000, MaxFee: 5000},
+		{TransactionType: TransactionTypePayout, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Fixed: 1500},
+	}}
+}
+
+func (suite *FeesTestSuite) TestFeeEstimateRequestIsValid() {
+	cases := map[string]FeeEstimateRequest{
+		`parameter "transaction_type" is empty`: {Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 100},
+		`parameter "method" is empty`:           {TransactionType: TransactionTypePayout, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 100},
+		`parameter "provider_id" is empty`:      {TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, Currency: CurrencyCodeUGX, Amount: 100},
+		`parameter "currency" is empty`:         {TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Amount: 100},
+		`parameter "amount" is empty`:           {TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX},
+	}
+	for message, request := range cases {
+		result := request.isValid()
+		assert.Error(suite.T(), result)
+		assert.Equal(suite.T(), message, result.Error())
+	}
+}
+
+func (suite *FeesTestSuite) TestNewFeeEstimateRequestFromCollection() {
+	result := NewFeeEstimateRequestFromCollection(&CollectionRequest{Currency: CurrencyCodeUGX, Amount: 100, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug"})
+	assert.Equal(suite.T(), &FeeEstimateRequest{TransactionType: TransactionTypeCollection, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 100}, result)
+}
+
+func (suite *FeesTestSuite) TestNewFeeEstimateRequestFromPayout() {
+	result := NewFeeEstimateRequestFromPayout(&PayoutRequest{Currency: CurrencyCodeUGX, Amount: 100, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug"})
+	assert.Equal(suite.T(), &FeeEstimateRequest{TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 100}, result)
+}
+
+func (suite *FeesTestSuite) TestFeeScheduleMostSpecificRule() {
+	request := &FeeEstimateRequest{TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 700}
+	result, err := suite.schedule.EstimateFee(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionTypePayout, result.TransactionType)
+	assert.Equal(suite.T(), CurrencyCodeUGX, result.Currency)
+	assert.Equal(suite.T(), float64(700), result.Amount)
+	assert.Equal(suite.T(), float64(1500), result.TransactionFee)
+	assert.Equal(suite.T(), float64(2200), result.Total)
+}
+
+func (suite *FeesTestSuite) TestFeeScheduleMinMaxFee() {
+	request := &FeeEstimateRequest{TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "airtel_ug", Currency: CurrencyCodeUGX, Amount: 100}
+	result, _ := suite.schedule.EstimateFee(suite.ctx, request)
+	assert.Equal(suite.T(), float64(1000), result.TransactionFee)
+	request.Amount = 1000000
+	result, _ = suite.schedule.EstimateFee(suite.ctx, request)
+	assert.Equal(suite.T(), float64(5000), result.TransactionFee)
+	request.Amount = 100000
+	result, _ = suite.schedule.EstimateFee(suite.ctx, request)
+	assert.Equal(suite.T(), float64(1500), result.TransactionFee)
+}
+
+func (suite *FeesTestSuite) TestFeeScheduleCollectionTotal() {
+	request := &FeeEstimateRequest{TransactionType: TransactionTypeCollection, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 737.9934}
+	result, err := suite.schedule.EstimateFee(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 22.1398, result.TransactionFee)
+	assert.Equal(suite.T(), 715.8536, result.Total)
+}
+
+func (suite *FeesTestSuite) TestFeeScheduleNoRule() {
+	schedule := &FeeSchedule{}
+	request := &FeeEstimateRequest{TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 700}
+	result, err := schedule.EstimateFee(suite.ctx, request)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "FeeSchedule.EstimateFee error: no fee rule for PAYOUT MOBILE_MONEY mtn_ug UGX", err.Error())
+}
+
+func (suite *FeesTestSuite) TestFeeScheduleInvalidRequest() {
+	result, err := suite.schedule.EstimateFee(suite.ctx, &FeeEstimateRequest{})
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *FeesTestSuite) TestFallbackFeeEstimator() {
+	request := &FeeEstimateRequest{TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 700}
+	estimator := NewFallbackFeeEstimator(&FeeSchedule{}, suite.schedule)
+	result, err := estimator.EstimateFee(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), float64(1500), result.TransactionFee)
+
+	estimator = NewFallbackFeeEstimator(suite.schedule, &FeeSchedule{})
+	result, err = estimator.EstimateFee(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), float64(1500), result.TransactionFee)
+
+	estimator = NewFallbackFeeEstimator(&FeeSchedule{}, &FeeSchedule{})
+	result, err = estimator.EstimateFee(suite.ctx, request)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func TestFeesTestSuite(t *testing.T) {
+	suite.Run(t, new(FeesTestSuite))
+}
+
+type FeesResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *FeesResource
+	request  *FeeEstimateRequest
+}
+
+func (suite *FeesResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &FeesResource{NewResourceAbstract(transport, cfg)}
+	suite.request = &FeeEstimateRequest{TransactionType: TransactionTypePayout, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug", Currency: CurrencyCodeUGX, Amount: 700}
+	httpmock.Activate()
+}
+
+func (suite *FeesResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateSuccess() {
+	body, _ := LoadStubResponseData("stubs/fees/estimate/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/fees/estimate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Estimate(suite.ctx, suite.request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "PAYOUT", result.Data.TransactionType)
+	assert.Equal(suite.T(), "MOBILE_MONEY", result.Data.Method)
+	assert.Equal(suite.T(), "mtn_ug", result.Data.ProviderID)
+	assert.Equal(suite.T(), "UGX", result.Data.Currency)
+	assert.Equal(suite.T(), float64(700), result.Data.Amount)
+	assert.Equal(suite.T(), float64(1500), result.Data.TransactionFee)
+	assert.Equal(suite.T(), float64(2200), result.Data.Total)
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/fees/estimate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Estimate(suite.ctx, suite.request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/fees/estimate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Estimate(suite.ctx, suite.request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateInvalidRequest() {
+	result, rsp, err := suite.testable.Estimate(suite.ctx, &FeeEstimateRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateFeeSuccess() {
+	body, _ := LoadStubResponseData("stubs/fees/estimate/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/fees/estimate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, err := suite.testable.EstimateFee(suite.ctx, suite.request)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), float64(1500), result.TransactionFee)
+	assert.Equal(suite.T(), float64(2200), result.Total)
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateFeeEmptyData() {
+	body := []byte(`{"code":200,"status":"success","message":"Request completed successfully.","data":null}`)
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/fees/estimate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, err := suite.testable.EstimateFee(suite.ctx, suite.request)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "FeesResource.EstimateFee error: empty response data", err.Error())
+}
+
+func (suite *FeesResourceTestSuite) TestEstimateFeeFallback() {
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/fees/estimate", httpmock.NewErrorResponder(errors.New("connection refused")))
+
+	schedule := &FeeSchedule{Rules: []*FeeRule{{Fixed: 1000}}}
+	result, err := NewFallbackFeeEstimator(suite.testable, schedule).EstimateFee(suite.ctx, suite.request)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), float64(1000), result.TransactionFee)
+	assert.Equal(suite.T(), float64(1700), result.Total)
+}
+
+func TestFeesResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(FeesResourceTestSuite))
+}
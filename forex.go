@@ -0,0 +1,178 @@
+package dusupay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+)
+
+//ForexRatesFilter forex rates filter
+type ForexRatesFilter struct {
+	//base currency rates are requested for
+	From CurrencyCode `json:"from_currency"`
+}
+
+//Check is valid ForexRatesFilter parameters
+func (frf *ForexRatesFilter) isValid() error {
+	var err error
+	if frf.From == "" {
+		err = fmt.Errorf(`parameter "from_currency" is empty`)
+	}
+	return err
+}
+
+//ForexQuoteRequest currency conversion quote request
+type ForexQuoteRequest struct {
+	From   CurrencyCode `json:"from_currency"`
+	To     CurrencyCode `json:"to_currency"`
+	Amount float64      `json:"amount"`
+}
+
+//Check is valid ForexQuoteRequest parameters
+func (fqr *ForexQuoteRequest) isValid() error {
+	var err error
+	if fqr.From == "" {
+		err = fmt.Errorf(`parameter "from_currency" is empty`)
+	} else if fqr.To == "" {
+		err = fmt.Errorf(`parameter "to_currency" is empty`)
+	} else if fqr.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	}
+	return err
+}
+
+//ForexRatesResponse struct
+type ForexRatesResponse struct {
+	ResponseBody
+	Data *ForexRatesResponseData `json:"data,omitempty"`
+}
+
+//ForexRatesResponseData struct
+type ForexRatesResponseData []*ForexRatesResponseDataItem
+
+//UnmarshalJSON unmarshal json data
+func (rsp *ForexRatesResponseData) UnmarshalJSON(data []byte) error {
+	if isEmptyObjectResponseData(data) {
+		return nil
+	}
+	var arr []*ForexRatesResponseDataItem
+	err := json.Unmarshal(data, &arr)
+	if err != nil {
+		return err
+	}
+	*rsp = append(*rsp, arr...)
+	return nil
+}
+
+//FindRate find conversion rate between currencies (inverse rate is used if there is no direct one)
+func (rsp *ForexRatesResponseData) FindRate(from CurrencyCode, to CurrencyCode) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	for _, item := range *rsp {
+		if item.From == string(from) && item.To == string(to) && item.Rate != 0 {
+			return item.Rate, true
+		}
+	}
+	for _, item := range *rsp {
+		if item.From == string(to) && item.To == string(from) && item.Rate != 0 {
+			return 1 / item.Rate, true
+		}
+	}
+	return 0, false
+}
+
+//PredictAccountAmount predict collection amount settled to the account currency wallet
+func (rsp *ForexRatesResponseData) PredictAccountAmount(req *CollectionRequest, accountCurrency CurrencyCode) (float64, error) {
+	rate, ok := rsp.FindRate(req.Currency, accountCurrency)
+	if !ok {
+		return 0, fmt.Errorf(`ForexRatesResponseData.PredictAccountAmount: rate "%s" => "%s" not found`, req.Currency, accountCurrency)
+	}
+	return roundAmount(req.Amount * rate), nil
+}
+
+//ForexRatesResponseDataItem struct
+type ForexRatesResponseDataItem struct {
+	From string  `json:"from_currency"`
+	To   string  `json:"to_currency"`
+	Rate float64 `json:"rate"`
+}
+
+//ForexQuoteResponse struct
+type ForexQuoteResponse struct {
+	ResponseBody
+	Data *ForexQuoteResponseData `json:"data,omitempty"`
+}
+
+//ForexQuoteResponseData struct
+type ForexQuoteResponseData struct {
+	From       string  `json:"from_currency"`
+	To         string  `json:"to_currency"`
+	Rate       float64 `json:"rate"`
+	FromAmount float64 `json:"from_amount"`
+	ToAmount   float64 `json:"to_amount"`
+	ExpiresAt  string  `json:"expires_at"`
+}
+
+//ForexResource wrapper
+type ForexResource struct {
+	ResourceAbstract
+}
+
+//GetRates get current forex rates (see https://docs.dusupay.com/appendix/forex-rates)
+func (r *ForexResource) GetRates(ctx context.Context, filter *ForexRatesFilter) (*ForexRatesResponse, *http.Response, error) {
+	err := filter.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("ForexResource.GetRates error: %v", err)
+	}
+	query, err := transformStructToMap(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ForexResource.GetRates error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/forex/rates", query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ForexResource.GetRates error: %v", err)
+	}
+	var result ForexRatesResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("ForexResource.GetRates error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//GetQuote get currency conversion quote (see https://docs.dusupay.com/appendix/forex-rates)
+func (r *ForexResource) GetQuote(ctx context.Context, req *ForexQuoteRequest) (*ForexQuoteResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("ForexResource.GetQuote error: %v", err)
+	}
+	query, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ForexResource.GetQuote error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/forex/quote", query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ForexResource.GetQuote error: %v", err)
+	}
+	var result ForexQuoteResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("ForexResource.GetQuote error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//roundAmount round amount to 4 decimal places (like Dusupay does)
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*10000) / 10000
+}
@@ -0,0 +1,210 @@
+package dusupay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type ForexTestSuite struct {
+	suite.Suite
+	rates *ForexRatesResponseData
+}
+
+func (suite *ForexTestSuite) SetupTest() {
+	suite.rates = &ForexRatesResponseData{
+		{From: "USD", To: "UGX", Rate: 3689.967},
+		{From: "USD", To: "KES", Rate: 125},
+	}
+}
+
+func (suite *ForexTestSuite) TestForexRatesFilterIsValid() {
+	filter := ForexRatesFilter{}
+	result := filter.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "from_currency" is empty`, result.Error())
+	filter.From = CurrencyCodeUSD
+	assert.NoError(suite.T(), filter.isValid())
+}
+
+func (suite *ForexTestSuite) TestForexQuoteRequestIsValid() {
+	cases := map[string]ForexQuoteRequest{
+		`parameter "from_currency" is empty`: {To: CurrencyCodeUGX, Amount: 10},
+		`parameter "to_currency" is empty`:   {From: CurrencyCodeUSD, Amount: 10},
+		`parameter "amount" is empty`:        {From: CurrencyCodeUSD, To: CurrencyCodeUGX},
+	}
+	for message, request := range cases {
+		result := request.isValid()
+		assert.Error(suite.T(), result)
+		assert.Equal(suite.T(), message, result.Error())
+	}
+	request := ForexQuoteRequest{From: CurrencyCodeUSD, To: CurrencyCodeUGX, Amount: 10}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *ForexTestSuite) TestFindRate() {
+	rate, ok := suite.rates.FindRate(CurrencyCodeUSD, CurrencyCodeKES)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), float64(125), rate)
+	rate, ok = suite.rates.FindRate(CurrencyCodeKES, CurrencyCodeUSD)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), 0.008, rate)
+	rate, ok = suite.rates.FindRate(CurrencyCodeUGX, CurrencyCodeUGX)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), float64(1), rate)
+	rate, ok = suite.rates.FindRate(CurrencyCodeUGX, CurrencyCodeKES)
+	assert.False(suite.T(), ok)
+	assert.Equal(suite.T(), float64(0), rate)
+}
+
+func (suite *ForexTestSuite) TestPredictAccountAmount() {
+	request := &CollectionRequest{Currency: CurrencyCodeUSD, Amount: 0.2}
+	result, err := suite.rates.PredictAccountAmount(request, CurrencyCodeUGX)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 737.9934, result)
+}
+
+func (suite *ForexTestSuite) TestPredictAccountAmountRateNotFound() {
+	request := &CollectionRequest{Currency: CurrencyCodeGBP, Amount: 10}
+	result, err := suite.rates.PredictAccountAmount(request, CurrencyCodeUGX)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), float64(0), result)
+	assert.Equal(suite.T(), `ForexRatesResponseData.PredictAccountAmount: rate "GBP" => "UGX" not found`, err.Error())
+}
+
+func TestForexTestSuite(t *testing.T) {
+	suite.Run(t, new(ForexTestSuite))
+}
+
+type ForexResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *ForexResource
+}
+
+func (suite *ForexResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &ForexResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *ForexResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *ForexResourceTestSuite) TestGetRatesSuccess() {
+	body, _ := LoadStubResponseData("stubs/forex/rates/success.json")
+	var query string
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/forex/rates", func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query().Get("from_currency")
+		return httpmock.NewBytesResponse(http.StatusOK, body), nil
+	})
+
+	result, resp, err := suite.testable.GetRates(suite.ctx, &ForexRatesFilter{From: CurrencyCodeUSD})
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	assert.Equal(suite.T(), "USD", query)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), http.StatusOK, result.Code)
+	assert.Equal(suite.T(), "success", result.Status)
+	assert.Equal(suite.T(), "Request completed successfully.", result.Message)
+	assert.Equal(suite.T(), "USD", (*result.Data)[0].From)
+	assert.Equal(suite.T(), "UGX", (*result.Data)[0].To)
+	assert.Equal(suite.T(), 3689.967, (*result.Data)[0].Rate)
+	assert.Equal(suite.T(), "KES", (*result.Data)[1].To)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *ForexResourceTestSuite) TestGetRatesJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/forex/rates", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetRates(suite.ctx, &ForexRatesFilter{From: CurrencyCodeUSD})
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *ForexResourceTestSuite) TestGetRatesNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/forex/rates", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetRates(suite.ctx, &ForexRatesFilter{From: CurrencyCodeUSD})
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *ForexResourceTestSuite) TestGetRatesInvalidFilter() {
+	result, rsp, err := suite.testable.GetRates(suite.ctx, &ForexRatesFilter{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ForexResourceTestSuite) TestGetQuoteSuccess() {
+	body, _ := LoadStubResponseData("stubs/forex/quote/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/forex/quote", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &ForexQuoteRequest{From: CurrencyCodeUSD, To: CurrencyCodeUGX, Amount: 10}
+	result, resp, err := suite.testable.GetQuote(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "USD", result.Data.From)
+	assert.Equal(suite.T(), "UGX", result.Data.To)
+	assert.Equal(suite.T(), 3689.967, result.Data.Rate)
+	assert.Equal(suite.T(), float64(10), result.Data.FromAmount)
+	assert.Equal(suite.T(), 36899.67, result.Data.ToAmount)
+	assert.Equal(suite.T(), "2022-06-01 12:05:00", result.Data.ExpiresAt)
+}
+
+func (suite *ForexResourceTestSuite) TestGetQuoteJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/forex/quote", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &ForexQuoteRequest{From: CurrencyCodeUSD, To: CurrencyCodeUGX, Amount: 10}
+	result, resp, err := suite.testable.GetQuote(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *ForexResourceTestSuite) TestGetQuoteNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/forex/quote", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &ForexQuoteRequest{From: CurrencyCodeUSD, To: CurrencyCodeUGX, Amount: 10}
+	result, resp, err := suite.testable.GetQuote(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *ForexResourceTestSuite) TestGetQuoteInvalidRequest() {
+	result, rsp, err := suite.testable.GetQuote(suite.ctx, &ForexQuoteRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func TestForexResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(ForexResourceTestSuite))
+}
@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/jarcoal/httpmock v1.1.0
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
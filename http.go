@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 //NewHttpTransport create new http transport
@@ -24,13 +25,31 @@ type Transport struct {
 	rb   *RequestBuilder
 }
 
-//SendRequest Send request method
+//SendRequest Send request method (failed GET requests are retried according to the config retry settings)
 func (tr *Transport) SendRequest(ctx context.Context, method string, path string, query map[string]interface{}, body map[string]interface{}) (resp *http.Response, err error) {
-	req, err := tr.rb.BuildRequest(ctx, method, path, query, body)
-	if err != nil {
-		return nil, fmt.Errorf("transport.SendRequest: %v", err)
+	attempts := 1
+	if strings.ToUpper(method) == http.MethodGet && tr.rb.cfg.MaxRetries > 0 {
+		attempts += tr.rb.cfg.MaxRetries
+	}
+	for attempt := 1; ; attempt++ {
+		req, err := tr.rb.BuildRequest(ctx, method, path, query, body)
+		if err != nil {
+			return nil, fmt.Errorf("transport.SendRequest: %v", err)
+		}
+		resp, err = tr.http.Do(req)
+		retryable := err != nil || resp.StatusCode >= http.StatusInternalServerError
+		if !retryable || attempt >= attempts {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(tr.rb.cfg.RetryDelay):
+		}
 	}
-	return tr.http.Do(req)
 }
 
 //Get method
@@ -71,22 +90,30 @@ func (rb *RequestBuilder) buildQueryParams(query map[string]interface{}) string
 }
 
 //BuildHeaders method
-func (rb *RequestBuilder) buildHeaders() http.Header {
+func (rb *RequestBuilder) buildHeaders(credentials *Credentials) http.Header {
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
-	headers.Set("secret-key", rb.cfg.SecretKey)
+	headers.Set("secret-key", credentials.SecretKey)
 	return headers
 }
 
 //BuildAuthParams method
-func (rb *RequestBuilder) buildAuthParams(params map[string]interface{}) map[string]interface{} {
+func (rb *RequestBuilder) buildAuthParams(params map[string]interface{}, credentials *Credentials) map[string]interface{} {
 	if params == nil {
 		params = make(map[string]interface{})
 	}
-	params["api_key"] = rb.cfg.PublicKey
+	params["api_key"] = credentials.PublicKey
 	return params
 }
 
+//Credentials get request credentials (the provider is consulted once per request, so the keys pair is consistent)
+func (rb *RequestBuilder) credentials(ctx context.Context) (*Credentials, error) {
+	if rb.cfg.CredentialsProvider != nil {
+		return rb.cfg.CredentialsProvider.GetCredentials(ctx)
+	}
+	return &Credentials{PublicKey: rb.cfg.PublicKey, SecretKey: rb.cfg.SecretKey}, nil
+}
+
 //BuildBody method
 func (rb *RequestBuilder) buildBody(data map[string]interface{}) (io.Reader, error) {
 	b, err := json.Marshal(data)
@@ -99,16 +126,20 @@ func (rb *RequestBuilder) buildBody(data map[string]interface{}) (io.Reader, err
 //BuildRequest method
 func (rb *RequestBuilder) BuildRequest(ctx context.Context, method string, path string, query map[string]interface{}, body map[string]interface{}) (req *http.Request, err error) {
 	method = strings.ToUpper(method)
+	credentials, err := rb.credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("transport.request credentials: %v", err)
+	}
 	//build body
 	var bodyReader io.Reader
 	if method == http.MethodPost {
-		body = rb.buildAuthParams(body)
+		body = rb.buildAuthParams(body, credentials)
 		bodyReader, err = rb.buildBody(body)
 		if err != nil {
 			return nil, fmt.Errorf("transport.request build request body: %v", err)
 		}
 	} else {
-		query = rb.buildAuthParams(query)
+		query = rb.buildAuthParams(query, credentials)
 	}
 	//build uri
 	uri, err := rb.buildUri(path, query)
@@ -121,7 +152,7 @@ func (rb *RequestBuilder) BuildRequest(ctx context.Context, method string, path
 		return nil, fmt.Errorf("transport.request new request error: %v", err)
 	}
 	//build headers
-	req.Header = rb.buildHeaders()
+	req.Header = rb.buildHeaders(credentials)
 	return req, nil
 }
 
@@ -137,6 +168,11 @@ func (r *ResponseBody) IsSuccess() bool {
 	return r.Code < http.StatusMultipleChoices
 }
 
+//isRejectedResponse check is request definitely rejected by the API (the response is parsed and is not successful)
+func isRejectedResponse(r *ResponseBody) bool {
+	return r.Code != 0 && !r.IsSuccess()
+}
+
 //UnmarshalResponse func
 func unmarshalResponse(resp *http.Response, v interface{}) error {
 	defer resp.Body.Close()
@@ -2,6 +2,7 @@ package dusupay
 
 import (
 	"context"
+	"errors"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -42,7 +43,7 @@ func (suite *HttpRequestBuilderTestSuite) TestBuildUriWithQueryParams() {
 }
 
 func (suite *HttpRequestBuilderTestSuite) TestBuildHeaders() {
-	headers := suite.testable.buildHeaders()
+	headers := suite.testable.buildHeaders(&Credentials{PublicKey: suite.cfg.PublicKey, SecretKey: suite.cfg.SecretKey})
 	assert.NotEmpty(suite.T(), headers)
 	assert.Equal(suite.T(), "application/json", headers.Get("Content-Type"))
 	assert.Equal(suite.T(), suite.cfg.SecretKey, headers.Get("secret-key"))
@@ -62,14 +63,14 @@ func (suite *HttpRequestBuilderTestSuite) TestBuildAuthParams() {
 	data["foo"] = "bar"
 	data["bar"] = "baz"
 
-	result := suite.testable.buildAuthParams(data)
+	result := suite.testable.buildAuthParams(data, &Credentials{PublicKey: suite.cfg.PublicKey, SecretKey: suite.cfg.SecretKey})
 	assert.Equal(suite.T(), data["foo"], result["foo"])
 	assert.Equal(suite.T(), data["bar"], result["bar"])
 	assert.Equal(suite.T(), suite.cfg.PublicKey, result["api_key"])
 }
 
 func (suite *HttpRequestBuilderTestSuite) TestBuildAuthParamsEmpty() {
-	result := suite.testable.buildAuthParams(nil)
+	result := suite.testable.buildAuthParams(nil, &Credentials{PublicKey: suite.cfg.PublicKey, SecretKey: suite.cfg.SecretKey})
 	assert.Equal(suite.T(), suite.cfg.PublicKey, result["api_key"])
 }
 
@@ -189,6 +190,70 @@ func (suite *HttpTransportTestSuite) TestPost() {
 	assert.Equal(suite.T(), body, bodyRsp)
 }
 
+func (suite *HttpTransportTestSuite) TestGetRetryServerError() {
+	body, _ := LoadStubResponseData("stubs/merchants/balance/success.json")
+	errorBody, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/foo?api_key=PublicKey", httpmock.ResponderFromMultipleResponses([]*http.Response{
+		httpmock.NewBytesResponse(http.StatusInternalServerError, errorBody),
+		httpmock.NewBytesResponse(http.StatusOK, body),
+	}))
+	suite.cfg.MaxRetries = 2
+
+	resp, err := suite.testable.Get(suite.ctx, "foo", nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), 2, httpmock.GetTotalCallCount())
+}
+
+func (suite *HttpTransportTestSuite) TestGetRetryExhausted() {
+	errorBody, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/foo?api_key=PublicKey", httpmock.NewBytesResponder(http.StatusBadGateway, errorBody))
+	suite.cfg.MaxRetries = 2
+
+	resp, err := suite.testable.Get(suite.ctx, "foo", nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(suite.T(), 3, httpmock.GetTotalCallCount())
+}
+
+func (suite *HttpTransportTestSuite) TestPostIsNotRetried() {
+	errorBody, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/foo", httpmock.NewBytesResponder(http.StatusInternalServerError, errorBody))
+	suite.cfg.MaxRetries = 2
+
+	resp, err := suite.testable.Post(suite.ctx, "foo", nil, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(suite.T(), 1, httpmock.GetTotalCallCount())
+}
+
+func (suite *HttpTransportTestSuite) TestGetWithCredentialsProvider() {
+	body, _ := LoadStubResponseData("stubs/merchants/balance/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/foo?api_key=RotatedPublicKey", httpmock.NewBytesResponder(http.StatusOK, body))
+	provider, _ := NewRotatingCredentialsProvider("PublicKey", "SecretKey")
+	suite.cfg.CredentialsProvider = provider
+	_ = provider.Rotate("RotatedPublicKey", "RotatedSecretKey")
+
+	resp, err := suite.testable.Get(suite.ctx, "foo", nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "RotatedSecretKey", resp.Request.Header.Get("secret-key"))
+}
+
+func (suite *HttpTransportTestSuite) TestPostCredentialsProviderError() {
+	suite.cfg.CredentialsProvider = &stubCredentialsProvider{err: errors.New("vault is sealed")}
+
+	resp, err := suite.testable.Post(suite.ctx, "foo", nil, nil)
+
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), resp)
+	assert.Contains(suite.T(), err.Error(), "transport.request credentials: vault is sealed")
+}
+
 func TestHttpTransportTestSuite(t *testing.T) {
 	suite.Run(t, new(HttpTransportTestSuite))
 }
@@ -0,0 +1,146 @@
+package dusupay
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+//USSDMessageMaxLength maximal length of the USSD message
+const USSDMessageMaxLength = 182
+
+//CollectionInstruction payment instruction step
+type CollectionInstruction struct {
+	StepNo      string `json:"step_no"`
+	Description string `json:"description"`
+}
+
+//CollectionInstructions payment instructions list
+type CollectionInstructions []*CollectionInstruction
+
+//instructionsLocale localised rendering strings
+type instructionsLocale struct {
+	title string
+	empty string
+}
+
+//instructionsLocaleEnglish default locale
+var instructionsLocaleEnglish = &instructionsLocale{
+	title: "To complete the payment:",
+	empty: "Please wait for the payment confirmation.",
+}
+
+//instructionsLocales per country localised rendering strings
+var instructionsLocales = map[CountryCode]*instructionsLocale{
+	CountryCodeCameroon: {
+		title: "Pour finaliser le paiement :",
+		empty: "Veuillez attendre la confirmation du paiement.",
+	},
+	CountryCodeBurundi: {
+		title: "Pour finaliser le paiement :",
+		empty: "Veuillez attendre la confirmation du paiement.",
+	},
+	CountryCodeTanzania: {
+		title: "Kukamilisha malipo:",
+		empty: "Tafadhali subiri uthibitisho wa malipo.",
+	},
+}
+
+//getInstructionsLocale get country locale or english by default
+func getInstructionsLocale(country CountryCode) *instructionsLocale {
+	if locale, ok := instructionsLocales[country]; ok {
+		return locale
+	}
+	return instructionsLocaleEnglish
+}
+
+//steps get instructions without the empty (JSON null) steps
+func (ci CollectionInstructions) steps() CollectionInstructions {
+	steps := make(CollectionInstructions, 0, len(ci))
+	for _, step := range ci {
+		if step != nil {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+//stepNo step number or its position if step number is empty
+func (ci CollectionInstructions) stepNo(i int) string {
+	if ci[i].StepNo != "" {
+		return ci[i].StepNo
+	}
+	return fmt.Sprintf("%d", i+1)
+}
+
+//Text render instructions as plain text
+func (ci CollectionInstructions) Text(country CountryCode) string {
+	locale := getInstructionsLocale(country)
+	ci = ci.steps()
+	if len(ci) == 0 {
+		return locale.empty
+	}
+	lines := []string{locale.title}
+	for i, step := range ci {
+		lines = append(lines, fmt.Sprintf("%s. %s", ci.stepNo(i), step.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//HTML render instructions as html ordered list
+func (ci CollectionInstructions) HTML(country CountryCode) string {
+	locale := getInstructionsLocale(country)
+	ci = ci.steps()
+	if len(ci) == 0 {
+		return "<p>" + html.EscapeString(locale.empty) + "</p>"
+	}
+	var sb strings.Builder
+	sb.WriteString("<p>" + html.EscapeString(locale.title) + "</p><ol>")
+	for i, step := range ci {
+		sb.WriteString(fmt.Sprintf(`<li value="%s">%s</li>`, html.EscapeString(ci.stepNo(i)), html.EscapeString(step.Description)))
+	}
+	sb.WriteString("</ol>")
+	return sb.String()
+}
+
+//USSD render instructions as compact USSD-style message (limited by USSDMessageMaxLength)
+func (ci CollectionInstructions) USSD(country CountryCode) string {
+	locale := getInstructionsLocale(country)
+	ci = ci.steps()
+	if len(ci) == 0 {
+		return truncateRunes(locale.empty, USSDMessageMaxLength)
+	}
+	lines := []string{locale.title}
+	for i, step := range ci {
+		lines = append(lines, fmt.Sprintf("%s.%s", ci.stepNo(i), strings.TrimSpace(step.Description)))
+	}
+	return truncateRunes(strings.Join(lines, "\n"), USSDMessageMaxLength)
+}
+
+//truncateRunes func
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+//CollectionNextActionCode type
+type CollectionNextActionCode string
+
+//CollectionNextActionRedirect const (redirect customer to the payment page)
+const CollectionNextActionRedirect CollectionNextActionCode = "REDIRECT"
+
+//CollectionNextActionInstructions const (show payment instructions to customer)
+const CollectionNextActionInstructions CollectionNextActionCode = "INSTRUCTIONS"
+
+//CollectionNextActionWait const (wait for the webhook)
+const CollectionNextActionWait CollectionNextActionCode = "WAIT"
+
+//CollectionNextAction what customer has to do next to complete the collection
+type CollectionNextAction struct {
+	Type         CollectionNextActionCode
+	PaymentURL   string
+	Instructions CollectionInstructions
+}
@@ -0,0 +1,83 @@
+package dusupay
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type InstructionsTestSuite struct {
+	suite.Suite
+	testable CollectionInstructions
+}
+
+func (suite *InstructionsTestSuite) SetupTest() {
+	suite.testable = CollectionInstructions{
+		{StepNo: "1", Description: "Ensure that you have sufficient balance on your MTN Mobile Money account"},
+		{StepNo: "2", Description: "Approve the payment request sent to your phone"},
+	}
+}
+
+func (suite *InstructionsTestSuite) TestText() {
+	expected := "To complete the payment:\n" +
+		"1. Ensure that you have sufficient balance on your MTN Mobile Money account\n" +
+		"2. Approve the payment request sent to your phone"
+	assert.Equal(suite.T(), expected, suite.testable.Text(CountryCodeUganda))
+}
+
+func (suite *InstructionsTestSuite) TestTextLocalised() {
+	assert.True(suite.T(), strings.HasPrefix(suite.testable.Text(CountryCodeCameroon), "Pour finaliser le paiement :\n"))
+	assert.True(suite.T(), strings.HasPrefix(suite.testable.Text(CountryCodeTanzania), "Kukamilisha malipo:\n"))
+}
+
+func (suite *InstructionsTestSuite) TestTextEmpty() {
+	assert.Equal(suite.T(), "Please wait for the payment confirmation.", CollectionInstructions{}.Text(CountryCodeUganda))
+}
+
+func (suite *InstructionsTestSuite) TestTextEmptyStepNo() {
+	instructions := CollectionInstructions{{Description: "foo"}, {Description: "bar"}}
+	assert.Equal(suite.T(), "To complete the payment:\n1. foo\n2. bar", instructions.Text(CountryCodeKenya))
+}
+
+func (suite *InstructionsTestSuite) TestNullStepsAreSkipped() {
+	var instructions CollectionInstructions
+	_ = json.Unmarshal([]byte(`[null,{"description":"foo"},null]`), &instructions)
+	assert.Equal(suite.T(), "To complete the payment:\n1. foo", instructions.Text(CountryCodeKenya))
+	assert.Equal(suite.T(), `<p>To complete the payment:</p><ol><li value="1">foo</li></ol>`, instructions.HTML(CountryCodeKenya))
+	assert.Equal(suite.T(), "To complete the payment:\n1.foo", instructions.USSD(CountryCodeKenya))
+	assert.Equal(suite.T(), "Please wait for the payment confirmation.", CollectionInstructions{nil}.Text(CountryCodeKenya))
+}
+
+func (suite *InstructionsTestSuite) TestHTML() {
+	instructions := CollectionInstructions{{StepNo: "1", Description: "Dial *165# & approve"}}
+	expected := `<p>To complete the payment:</p><ol><li value="1">Dial *165# &amp; approve</li></ol>`
+	assert.Equal(suite.T(), expected, instructions.HTML(CountryCodeUganda))
+}
+
+func (suite *InstructionsTestSuite) TestHTMLEmpty() {
+	assert.Equal(suite.T(), "<p>Veuillez attendre la confirmation du paiement.</p>", CollectionInstructions{}.HTML(CountryCodeBurundi))
+}
+
+func (suite *InstructionsTestSuite) TestUSSD() {
+	expected := "To complete the payment:\n" +
+		"1.Ensure that you have sufficient balance on your MTN Mobile Money account\n" +
+		"2.Approve the payment request sent to your phone"
+	assert.Equal(suite.T(), expected, suite.testable.USSD(CountryCodeUganda))
+}
+
+func (suite *InstructionsTestSuite) TestUSSDTruncated() {
+	instructions := CollectionInstructions{{StepNo: "1", Description: strings.Repeat("a", 300)}}
+	result := instructions.USSD(CountryCodeUganda)
+	assert.Equal(suite.T(), USSDMessageMaxLength, len([]rune(result)))
+	assert.True(suite.T(), strings.HasSuffix(result, "..."))
+}
+
+func (suite *InstructionsTestSuite) TestUSSDEmpty() {
+	assert.Equal(suite.T(), "Tafadhali subiri uthibitisho wa malipo.", CollectionInstructions{}.USSD(CountryCodeTanzania))
+}
+
+func TestInstructionsTestSuite(t *testing.T) {
+	suite.Run(t, new(InstructionsTestSuite))
+}
@@ -37,6 +37,28 @@ type BalancesResponseDataItem struct {
 	Balance  float64 `json:"balance"`
 }
 
+//GetBalance get currency wallet balance
+func (brd *BalancesResponseData) GetBalance(currency CurrencyCode) (float64, bool) {
+	for _, item := range *brd {
+		if item.Currency == string(currency) {
+			return item.Balance, true
+		}
+	}
+	return 0, false
+}
+
+//CheckSufficientBalance check is currency wallet balance covers the amount
+func (brd *BalancesResponseData) CheckSufficientBalance(currency CurrencyCode, amount float64) error {
+	balance, ok := brd.GetBalance(currency)
+	if !ok {
+		return fmt.Errorf(`balance "%s" not found`, currency)
+	}
+	if balance < amount {
+		return fmt.Errorf(`insufficient "%s" balance: %v < %v`, currency, balance, amount)
+	}
+	return nil
+}
+
 //MerchantsResource wrapper
 type MerchantsResource struct {
 	ResourceAbstract
@@ -10,6 +10,31 @@ import (
 	"testing"
 )
 
+type MerchantsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MerchantsTestSuite) TestBalancesResponseDataGetBalance() {
+	balances := &BalancesResponseData{{Currency: "UGX", Balance: 5475.816}, {Currency: "USD", Balance: 12}}
+	balance, ok := balances.GetBalance(CurrencyCodeUSD)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), float64(12), balance)
+	balance, ok = balances.GetBalance(CurrencyCodeKES)
+	assert.False(suite.T(), ok)
+	assert.Equal(suite.T(), float64(0), balance)
+}
+
+func (suite *MerchantsTestSuite) TestBalancesResponseDataCheckSufficientBalance() {
+	balances := &BalancesResponseData{{Currency: "UGX", Balance: 5475.816}}
+	assert.NoError(suite.T(), balances.CheckSufficientBalance(CurrencyCodeUGX, 5475.816))
+	assert.Error(suite.T(), balances.CheckSufficientBalance(CurrencyCodeUGX, 5476))
+	assert.Error(suite.T(), balances.CheckSufficientBalance(CurrencyCodeUSD, 1))
+}
+
+func TestMerchantsTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantsTestSuite))
+}
+
 type MerchantsResourceTestSuite struct {
 	suite.Suite
 	cfg      *Config
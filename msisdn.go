@@ -0,0 +1,278 @@
+package dusupay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//msisdnRule country phone numbers plan
+type msisdnRule struct {
+	dialCode string
+	length   int
+	prefixes []string
+	//national number prefix => mobile money provider id
+	providers map[string]string
+}
+
+//msisdnRules supported countries phone numbers plans
+var msisdnRules = map[CountryCode]*msisdnRule{
+	CountryCodeUganda: {
+		dialCode: "256",
+		length:   9,
+		prefixes: []string{"70", "71", "72", "74", "75", "76", "77", "78", "79", "20", "39"},
+		providers: map[string]string{
+			"76": "mtn_ug", "77": "mtn_ug", "78": "mtn_ug", "39": "mtn_ug",
+			"70": "airtel_ug", "74": "airtel_ug", "75": "airtel_ug", "20": "airtel_ug",
+		},
+	},
+	CountryCodeKenya: {
+		dialCode: "254",
+		length:   9,
+		prefixes: []string{"7", "1"},
+		providers: map[string]string{
+			"70": "mpesa_ke", "71": "mpesa_ke", "72": "mpesa_ke", "740": "mpesa_ke", "741": "mpesa_ke",
+			"742": "mpesa_ke", "743": "mpesa_ke", "745": "mpesa_ke", "746": "mpesa_ke", "748": "mpesa_ke",
+			"757": "mpesa_ke", "758": "mpesa_ke", "759": "mpesa_ke", "768": "mpesa_ke", "769": "mpesa_ke",
+			"79": "mpesa_ke", "110": "mpesa_ke", "111": "mpesa_ke", "112": "mpesa_ke", "113": "mpesa_ke",
+			"114": "mpesa_ke", "115": "mpesa_ke",
+			"73": "airtel_ke", "750": "airtel_ke", "751": "airtel_ke", "752": "airtel_ke", "753": "airtel_ke",
+			"754": "airtel_ke", "755": "airtel_ke", "756": "airtel_ke", "762": "airtel_ke", "78": "airtel_ke",
+			"100": "airtel_ke", "101": "airtel_ke", "102": "airtel_ke",
+		},
+	},
+	CountryCodeTanzania: {
+		dialCode: "255",
+		length:   9,
+		prefixes: []string{"6", "7"},
+		providers: map[string]string{
+			"74": "vodacom_tz", "75": "vodacom_tz", "76": "vodacom_tz",
+			"68": "airtel_tz", "69": "airtel_tz", "78": "airtel_tz",
+			"65": "tigo_tz", "67": "tigo_tz", "71": "tigo_tz",
+			"62": "halopesa_tz",
+		},
+	},
+	CountryCodeRwanda: {
+		dialCode: "250",
+		length:   9,
+		prefixes: []string{"72", "73", "78", "79"},
+		providers: map[string]string{
+			"78": "mtn_rw", "79": "mtn_rw",
+			"72": "airtel_rw", "73": "airtel_rw",
+		},
+	},
+	CountryCodeZambia: {
+		dialCode: "260",
+		length:   9,
+		prefixes: []string{"75", "76", "77", "95", "96", "97"},
+		providers: map[string]string{
+			"76": "mtn_zm", "96": "mtn_zm",
+			"77": "airtel_zm", "97": "airtel_zm",
+			"75": "zamtel_zm", "95": "zamtel_zm",
+		},
+	},
+	CountryCodeGhana: {
+		dialCode: "233",
+		length:   9,
+		prefixes: []string{"20", "23", "24", "25", "26", "27", "28", "50", "53", "54", "55", "56", "57", "59"},
+		providers: map[string]string{
+			"24": "mtn_gh", "25": "mtn_gh", "53": "mtn_gh", "54": "mtn_gh", "55": "mtn_gh", "59": "mtn_gh",
+			"20": "vodafone_gh", "50": "vodafone_gh",
+			"26": "airteltigo_gh", "27": "airteltigo_gh", "56": "airteltigo_gh", "57": "airteltigo_gh",
+		},
+	},
+	CountryCodeCameroon: {
+		dialCode: "237",
+		length:   9,
+		prefixes: []string{"6"},
+		providers: map[string]string{
+			"67": "mtn_cm", "650": "mtn_cm", "651": "mtn_cm", "652": "mtn_cm", "653": "mtn_cm", "654": "mtn_cm",
+			"680": "mtn_cm", "681": "mtn_cm", "682": "mtn_cm", "683": "mtn_cm",
+			"69": "orange_cm", "655": "orange_cm", "656": "orange_cm", "657": "orange_cm", "658": "orange_cm",
+			"659": "orange_cm",
+		},
+	},
+	CountryCodeBurundi: {
+		dialCode: "257",
+		length:   8,
+		prefixes: []string{"6", "7"},
+	},
+	CountryCodeNigeria: {
+		dialCode: "234",
+		length:   10,
+		prefixes: []string{"70", "80", "81", "90", "91"},
+	},
+	CountryCodeSouthAfrica: {
+		dialCode: "27",
+		length:   9,
+		prefixes: []string{"6", "7", "8"},
+	},
+}
+
+//MSISDN mobile subscriber number
+type MSISDN struct {
+	Country        CountryCode `json:"country"`
+	DialCode       string      `json:"dial_code"`
+	NationalNumber string      `json:"national_number"`
+}
+
+//ParseMSISDN normalize raw user input (0772..., +256772..., 256 772 ...) to the country mobile number
+func ParseMSISDN(raw string, country CountryCode) (*MSISDN, error) {
+	rule, ok := msisdnRules[country]
+	if !ok {
+		return nil, fmt.Errorf(`ParseMSISDN: country "%s" is not supported`, country)
+	}
+	digits, international, ok := cleanMSISDN(raw)
+	if !ok {
+		return nil, fmt.Errorf(`ParseMSISDN: number "%s" contains wrong characters`, raw)
+	} else if digits == "" {
+		return nil, fmt.Errorf(`ParseMSISDN: number "%s" is empty`, raw)
+	}
+	national := digits
+	if strings.HasPrefix(digits, rule.dialCode) && (international || len(digits) == len(rule.dialCode)+rule.length) {
+		national = strings.TrimPrefix(digits, rule.dialCode)
+	} else if international {
+		return nil, fmt.Errorf(`ParseMSISDN: number "%s" does not belong to country "%s"`, raw, country)
+	} else if strings.HasPrefix(digits, "0") {
+		national = strings.TrimPrefix(digits, "0")
+	}
+	if len(national) != rule.length {
+		return nil, fmt.Errorf(`ParseMSISDN: number "%s" has wrong length`, raw)
+	}
+	if !hasAnyPrefix(national, rule.prefixes) {
+		return nil, fmt.Errorf(`ParseMSISDN: number "%s" has wrong mobile prefix`, raw)
+	}
+	return &MSISDN{Country: country, DialCode: rule.dialCode, NationalNumber: national}, nil
+}
+
+//providerCountry get country code from the provider id suffix (e.g. mtn_ug -> UG)
+func providerCountry(providerId string) CountryCode {
+	i := strings.LastIndex(providerId, "_")
+	if i < 0 {
+		return ""
+	}
+	return CountryCode(strings.ToUpper(providerId[i+1:]))
+}
+
+//E164 number in E.164 format (+256772123456)
+func (m *MSISDN) E164() string {
+	return "+" + m.AccountNumber()
+}
+
+//String method
+func (m *MSISDN) String() string {
+	return m.E164()
+}
+
+//AccountNumber number in format expected by Dusupay "account_number" parameter (256772123456)
+func (m *MSISDN) AccountNumber() string {
+	return m.DialCode + m.NationalNumber
+}
+
+//ProviderId infer the likely mobile money provider id by number prefix
+func (m *MSISDN) ProviderId() (string, error) {
+	rule := msisdnRules[m.Country]
+	if rule != nil {
+		//check the longest prefixes first
+		prefixes := make([]string, 0, len(rule.providers))
+		for prefix := range rule.providers {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Slice(prefixes, func(i, j int) bool {
+			return len(prefixes[i]) > len(prefixes[j])
+		})
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(m.NationalNumber, prefix) {
+				return rule.providers[prefix], nil
+			}
+		}
+	}
+	return "", fmt.Errorf(`MSISDN.ProviderId: unable to infer provider for number "%s"`, m.E164())
+}
+
+//NormalizeMSISDN normalize raw phone number to E.164 format
+func NormalizeMSISDN(raw string, country CountryCode) (string, error) {
+	m, err := ParseMSISDN(raw, country)
+	if err != nil {
+		return "", err
+	}
+	return m.E164(), nil
+}
+
+//InferMobileMoneyProvider infer the likely mobile money provider id of raw phone number
+func InferMobileMoneyProvider(raw string, country CountryCode) (string, error) {
+	m, err := ParseMSISDN(raw, country)
+	if err != nil {
+		return "", err
+	}
+	return m.ProviderId()
+}
+
+//SetMobileMoneyAccount fill mobile money account number and provider from raw phone number
+func (cr *CollectionRequest) SetMobileMoneyAccount(raw string, country CountryCode) error {
+	m, providerId, err := parseMobileMoneyAccount(raw, country, cr.ProviderId)
+	if err != nil {
+		return fmt.Errorf("CollectionRequest.SetMobileMoneyAccount error: %v", err)
+	}
+	cr.Method = TransactionMethodMobileMoney
+	cr.AccountNumber = m.AccountNumber()
+	cr.ProviderId = providerId
+	return nil
+}
+
+//SetMobileMoneyAccount fill mobile money account number and provider from raw phone number
+func (pr *PayoutRequest) SetMobileMoneyAccount(raw string, country CountryCode) error {
+	m, providerId, err := parseMobileMoneyAccount(raw, country, pr.ProviderId)
+	if err != nil {
+		return fmt.Errorf("PayoutRequest.SetMobileMoneyAccount error: %v", err)
+	}
+	pr.Method = TransactionMethodMobileMoney
+	pr.AccountNumber = m.AccountNumber()
+	pr.ProviderId = providerId
+	return nil
+}
+
+//parseMobileMoneyAccount parse number and infer provider if it is not defined yet
+func parseMobileMoneyAccount(raw string, country CountryCode, providerId string) (*MSISDN, string, error) {
+	m, err := ParseMSISDN(raw, country)
+	if err != nil {
+		return nil, "", err
+	}
+	if providerId == "" {
+		providerId, err = m.ProviderId()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return m, providerId, nil
+}
+
+//cleanMSISDN strip formatting characters and international call prefix
+func cleanMSISDN(raw string) (string, bool, bool) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+")
+	var sb strings.Builder
+	for _, r := range strings.TrimPrefix(raw, "+") {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		} else if !strings.ContainsRune(" -.()", r) {
+			return "", false, false
+		}
+	}
+	digits := sb.String()
+	if !international && strings.HasPrefix(digits, "00") {
+		digits = strings.TrimPrefix(digits, "00")
+		international = true
+	}
+	return digits, international, true
+}
+
+//hasAnyPrefix func
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
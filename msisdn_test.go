@@ -0,0 +1,144 @@
+package dusupay
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type MSISDNTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNFormats() {
+	inputs := []string{"0772123456", "+256772123456", "256 772 123 456", "00256-772-123-456", "772123456", "(0772) 123 456"}
+	for _, input := range inputs {
+		result, err := ParseMSISDN(input, CountryCodeUganda)
+		assert.NoError(suite.T(), err, input)
+		assert.Equal(suite.T(), CountryCodeUganda, result.Country)
+		assert.Equal(suite.T(), "256", result.DialCode)
+		assert.Equal(suite.T(), "772123456", result.NationalNumber)
+		assert.Equal(suite.T(), "+256772123456", result.E164())
+		assert.Equal(suite.T(), "+256772123456", result.String())
+		assert.Equal(suite.T(), "256772123456", result.AccountNumber())
+	}
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNUnsupportedCountry() {
+	result, err := ParseMSISDN("0772123456", CountryCodeUSA)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `ParseMSISDN: country "US" is not supported`, err.Error())
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNEmpty() {
+	result, err := ParseMSISDN(" ", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `ParseMSISDN: number " " is empty`, err.Error())
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNWrongCharacters() {
+	_, err := ParseMSISDN("0772abc456", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `ParseMSISDN: number "0772abc456" contains wrong characters`, err.Error())
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNOtherCountry() {
+	_, err := ParseMSISDN("+254712123456", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `ParseMSISDN: number "+254712123456" does not belong to country "UG"`, err.Error())
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNWrongLength() {
+	_, err := ParseMSISDN("077212345", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `ParseMSISDN: number "077212345" has wrong length`, err.Error())
+}
+
+func (suite *MSISDNTestSuite) TestParseMSISDNWrongPrefix() {
+	_, err := ParseMSISDN("0412123456", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `ParseMSISDN: number "0412123456" has wrong mobile prefix`, err.Error())
+}
+
+func (suite *MSISDNTestSuite) TestNormalizeMSISDN() {
+	result, err := NormalizeMSISDN("0712 123 456", CountryCodeKenya)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "+254712123456", result)
+	_, err = NormalizeMSISDN("0712", CountryCodeKenya)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *MSISDNTestSuite) TestInferMobileMoneyProvider() {
+	cases := []struct {
+		raw      string
+		country  CountryCode
+		provider string
+	}{
+		{"0772123456", CountryCodeUganda, "mtn_ug"},
+		{"0752123456", CountryCodeUganda, "airtel_ug"},
+		{"0712123456", CountryCodeKenya, "mpesa_ke"},
+		{"0758123456", CountryCodeKenya, "mpesa_ke"},
+		{"0752123456", CountryCodeKenya, "airtel_ke"},
+		{"0788123456", CountryCodeRwanda, "mtn_rw"},
+		{"0732123456", CountryCodeRwanda, "airtel_rw"},
+		{"0961123456", CountryCodeZambia, "mtn_zm"},
+		{"0971123456", CountryCodeZambia, "airtel_zm"},
+		{"0241234567", CountryCodeGhana, "mtn_gh"},
+		{"0655123456", CountryCodeCameroon, "orange_cm"},
+		{"0650123456", CountryCodeCameroon, "mtn_cm"},
+	}
+	for _, c := range cases {
+		result, err := InferMobileMoneyProvider(c.raw, c.country)
+		assert.NoError(suite.T(), err, c.raw)
+		assert.Equal(suite.T(), c.provider, result, c.raw)
+	}
+}
+
+func (suite *MSISDNTestSuite) TestInferMobileMoneyProviderUnknown() {
+	result, err := InferMobileMoneyProvider("0712345678", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "", result)
+	assert.Equal(suite.T(), `MSISDN.ProviderId: unable to infer provider for number "+256712345678"`, err.Error())
+	_, err = InferMobileMoneyProvider("07", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *MSISDNTestSuite) TestCollectionRequestSetMobileMoneyAccount() {
+	request := &CollectionRequest{}
+	err := request.SetMobileMoneyAccount("0752 123 456", CountryCodeUganda)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodMobileMoney, request.Method)
+	assert.Equal(suite.T(), "256752123456", request.AccountNumber)
+	assert.Equal(suite.T(), "airtel_ug", request.ProviderId)
+}
+
+func (suite *MSISDNTestSuite) TestCollectionRequestSetMobileMoneyAccountKeepProvider() {
+	request := &CollectionRequest{ProviderId: "mtn_ug"}
+	err := request.SetMobileMoneyAccount("0752123456", CountryCodeUganda)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "mtn_ug", request.ProviderId)
+}
+
+func (suite *MSISDNTestSuite) TestCollectionRequestSetMobileMoneyAccountError() {
+	request := &CollectionRequest{}
+	err := request.SetMobileMoneyAccount("0712345678", CountryCodeUganda)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "", request.AccountNumber)
+}
+
+func (suite *MSISDNTestSuite) TestPayoutRequestSetMobileMoneyAccount() {
+	request := &PayoutRequest{}
+	err := request.SetMobileMoneyAccount("+254 712 123 456", CountryCodeKenya)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodMobileMoney, request.Method)
+	assert.Equal(suite.T(), "254712123456", request.AccountNumber)
+	assert.Equal(suite.T(), "mpesa_ke", request.ProviderId)
+	err = request.SetMobileMoneyAccount("123", CountryCodeKenya)
+	assert.Error(suite.T(), err)
+}
+
+func TestMSISDNTestSuite(t *testing.T) {
+	suite.Run(t, new(MSISDNTestSuite))
+}
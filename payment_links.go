@@ -0,0 +1,251 @@
+package dusupay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//PaymentLinkExpiryLayout payment link expiry date format
+const PaymentLinkExpiryLayout = "2006-01-02 15:04:05"
+
+//PaymentLinkStatusCode type
+type PaymentLinkStatusCode string
+
+//PaymentLinkStatusActive const
+const PaymentLinkStatusActive PaymentLinkStatusCode = "ACTIVE"
+
+//PaymentLinkStatusInactive const
+const PaymentLinkStatusInactive PaymentLinkStatusCode = "INACTIVE"
+
+//PaymentLinkStatusExpired const
+const PaymentLinkStatusExpired PaymentLinkStatusCode = "EXPIRED"
+
+//PaymentLinkStatusPaid const
+const PaymentLinkStatusPaid PaymentLinkStatusCode = "PAID"
+
+//PaymentLinkRequest payment link (invoice) which can be sent to the customer instead of the checkout integration
+type PaymentLinkRequest struct {
+	Currency          CurrencyCode            `json:"currency"`
+	Amount            float64                 `json:"amount"`
+	AllowedMethods    []TransactionMethodCode `json:"allowed_methods,omitempty"`
+	MerchantReference string                  `json:"merchant_reference"`
+	Narration         string                  `json:"narration"`
+	CustomerEmail     string                  `json:"customer_email,omitempty"`
+	RedirectUrl       string                  `json:"redirect_url,omitempty"`
+	ExpiresAt         string                  `json:"expires_at,omitempty"`
+}
+
+//SetExpiry set payment link expiry date
+func (pr *PaymentLinkRequest) SetExpiry(expiresAt time.Time) {
+	pr.ExpiresAt = expiresAt.UTC().Format(PaymentLinkExpiryLayout)
+}
+
+//Check is valid PaymentLinkRequest parameters
+func (pr *PaymentLinkRequest) isValid() error {
+	var err error
+	if pr.Currency == "" {
+		err = fmt.Errorf(`parameter "currency" is empty`)
+	} else if pr.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	} else if pr.Amount < 0 {
+		err = fmt.Errorf(`parameter "amount" is negative`)
+	} else if pr.MerchantReference == "" {
+		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
+	} else if pr.Narration == "" {
+		err = fmt.Errorf(`parameter "narration" is empty`)
+	} else if pr.ExpiresAt != "" && !isValidPaymentLinkExpiry(pr.ExpiresAt) {
+		err = fmt.Errorf(`parameter "expires_at" is invalid`)
+	} else if !isValidPaymentLinkMethods(pr.AllowedMethods) {
+		err = fmt.Errorf(`parameter "allowed_methods" is invalid`)
+	}
+	return err
+}
+
+//IsValidPaymentLinkExpiry func
+func isValidPaymentLinkExpiry(expiresAt string) bool {
+	_, err := time.Parse(PaymentLinkExpiryLayout, expiresAt)
+	return err == nil
+}
+
+//IsValidPaymentLinkMethods func
+func isValidPaymentLinkMethods(methods []TransactionMethodCode) bool {
+	for _, method := range methods {
+		if !isValidTransactionMethod(method) {
+			return false
+		}
+	}
+	return true
+}
+
+//PaymentLinksFilter payment links list filter (all parameters are optional)
+type PaymentLinksFilter struct {
+	Status            PaymentLinkStatusCode `json:"status,omitempty"`
+	MerchantReference string                `json:"merchant_reference,omitempty"`
+	Page              int                   `json:"page,omitempty"`
+}
+
+//PaymentLinkResponse struct
+type PaymentLinkResponse struct {
+	ResponseBody
+	Data *PaymentLinkResponseData `json:"data,omitempty"`
+}
+
+//PaymentLinkResponseData struct
+type PaymentLinkResponseData struct {
+	ID                int64    `json:"id"`
+	PaymentLinkID     string   `json:"payment_link_id"`
+	RequestAmount     float64  `json:"request_amount"`
+	RequestCurrency   string   `json:"request_currency"`
+	AllowedMethods    []string `json:"allowed_methods"`
+	MerchantReference string   `json:"merchant_reference"`
+	Narration         string   `json:"narration"`
+	CustomerEmail     string   `json:"customer_email"`
+	PaymentURL        string   `json:"payment_url"`
+	Status            string   `json:"status"`
+	ExpiresAt         string   `json:"expires_at"`
+	CreatedAt         string   `json:"created_at"`
+}
+
+//IsActive check is payment link can still be paid
+func (pl *PaymentLinkResponseData) IsActive() bool {
+	return PaymentLinkStatusCode(pl.Status) == PaymentLinkStatusActive
+}
+
+//MatchCollectionWebhook check is collection webhook was paid through the payment link
+func (pl *PaymentLinkResponseData) MatchCollectionWebhook(webhook *CollectionWebhook) bool {
+	if webhook.PaymentLinkID != "" {
+		return webhook.PaymentLinkID == pl.PaymentLinkID
+	}
+	return pl.MerchantReference != "" && webhook.MerchantReference == pl.MerchantReference
+}
+
+//PaymentLinksResponse struct
+type PaymentLinksResponse struct {
+	ResponseBody
+	Data *PaymentLinksResponseData `json:"data,omitempty"`
+}
+
+//PaymentLinksResponseData struct
+type PaymentLinksResponseData []*PaymentLinkResponseData
+
+//UnmarshalJSON unmarshal json data
+func (rsp *PaymentLinksResponseData) UnmarshalJSON(data []byte) error {
+	if isEmptyObjectResponseData(data) {
+		return nil
+	}
+	var arr []*PaymentLinkResponseData
+	err := json.Unmarshal(data, &arr)
+	if err != nil {
+		return err
+	}
+	*rsp = append(*rsp, arr...)
+	return nil
+}
+
+//FindByCollectionWebhook find payment link paid by the collection webhook
+func (rsp *PaymentLinksResponseData) FindByCollectionWebhook(webhook *CollectionWebhook) (*PaymentLinkResponseData, bool) {
+	for _, link := range *rsp {
+		if link.MatchCollectionWebhook(webhook) {
+			return link, true
+		}
+	}
+	return nil, false
+}
+
+//PaymentLinksResource wrapper
+type PaymentLinksResource struct {
+	ResourceAbstract
+}
+
+//Create payment link (see https://docs.dusupay.com/receiving-money/payment-links)
+func (r *PaymentLinksResource) Create(ctx context.Context, req *PaymentLinkRequest) (*PaymentLinkResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.Create error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.Create error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/payment-links", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.Create error: %v", err)
+	}
+	var result PaymentLinkResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("PaymentLinksResource.Create error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//GetList get payment links list (see https://docs.dusupay.com/receiving-money/payment-links)
+func (r *PaymentLinksResource) GetList(ctx context.Context, filter *PaymentLinksFilter) (*PaymentLinksResponse, *http.Response, error) {
+	if filter == nil {
+		filter = &PaymentLinksFilter{}
+	}
+	query, err := transformStructToMap(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.GetList error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/payment-links", query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.GetList error: %v", err)
+	}
+	var result PaymentLinksResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("PaymentLinksResource.GetList error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//Get payment link by id (see https://docs.dusupay.com/receiving-money/payment-links)
+func (r *PaymentLinksResource) Get(ctx context.Context, paymentLinkId string) (*PaymentLinkResponse, *http.Response, error) {
+	if paymentLinkId == "" {
+		return nil, nil, fmt.Errorf(`PaymentLinksResource.Get error: parameter "payment_link_id" is empty`)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/payment-links/"+paymentLinkId, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.Get error: %v", err)
+	}
+	var result PaymentLinkResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("PaymentLinksResource.Get error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//Deactivate payment link so it can not be paid anymore (see https://docs.dusupay.com/receiving-money/payment-links)
+func (r *PaymentLinksResource) Deactivate(ctx context.Context, paymentLinkId string) (*PaymentLinkResponse, *http.Response, error) {
+	if paymentLinkId == "" {
+		return nil, nil, fmt.Errorf(`PaymentLinksResource.Deactivate error: parameter "payment_link_id" is empty`)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/payment-links/"+paymentLinkId+"/deactivate", nil, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PaymentLinksResource.Deactivate error: %v", err)
+	}
+	var result PaymentLinkResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("PaymentLinksResource.Deactivate error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
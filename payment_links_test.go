@@ -0,0 +1,277 @@
+package dusupay
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type PaymentLinksTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PaymentLinksTestSuite) TestPaymentLinkRequestIsValidSuccess() {
+	request := PaymentLinkRequest{
+		Currency:          CurrencyCodeUGX,
+		Amount:            25000,
+		AllowedMethods:    []TransactionMethodCode{TransactionMethodMobileMoney, TransactionMethodCard},
+		MerchantReference: "invoice-1001",
+		Narration:         "Invoice 1001",
+	}
+	request.SetExpiry(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(suite.T(), request.isValid())
+	assert.Equal(suite.T(), "2022-07-01 00:00:00", request.ExpiresAt)
+}
+
+func (suite *PaymentLinksTestSuite) TestPaymentLinkRequestIsValidEmptyAmount() {
+	request := PaymentLinkRequest{Currency: CurrencyCodeUGX, MerchantReference: "invoice-1001", Narration: "Invoice 1001"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "amount" is empty`, result.Error())
+}
+
+func (suite *PaymentLinksTestSuite) TestPaymentLinkRequestIsValidNegativeAmount() {
+	request := PaymentLinkRequest{Currency: CurrencyCodeUGX, Amount: -100, MerchantReference: "invoice-1001", Narration: "Invoice 1001"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "amount" is negative`, result.Error())
+}
+
+func (suite *PaymentLinksTestSuite) TestPaymentLinkRequestIsValidEmptyMerchantReference() {
+	request := PaymentLinkRequest{Currency: CurrencyCodeUGX, Amount: 100, Narration: "Invoice 1001"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "merchant_reference" is empty`, result.Error())
+}
+
+func (suite *PaymentLinksTestSuite) TestPaymentLinkRequestIsValidWrongExpiry() {
+	request := PaymentLinkRequest{Currency: CurrencyCodeUGX, Amount: 100, MerchantReference: "invoice-1001", Narration: "Invoice 1001", ExpiresAt: "01.07.2022"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "expires_at" is invalid`, result.Error())
+}
+
+func (suite *PaymentLinksTestSuite) TestPaymentLinkRequestIsValidWrongMethods() {
+	request := PaymentLinkRequest{Currency: CurrencyCodeUGX, Amount: 100, MerchantReference: "invoice-1001", Narration: "Invoice 1001", AllowedMethods: []TransactionMethodCode{""}}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "allowed_methods" is invalid`, result.Error())
+
+	request.AllowedMethods = []TransactionMethodCode{TransactionMethodCard, "CASH"}
+	result = request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "allowed_methods" is invalid`, result.Error())
+}
+
+func (suite *PaymentLinksTestSuite) TestFindByCollectionWebhook() {
+	body, _ := LoadStubResponseData("stubs/payment-links/list/success.json")
+	var response PaymentLinksResponse
+	_ = json.Unmarshal(body, &response)
+	webhookBody, _ := LoadStubResponseData("stubs/webhooks/request/collection-payment-link-success.json")
+	var webhook CollectionWebhook
+	_ = json.Unmarshal(webhookBody, &webhook)
+
+	assert.True(suite.T(), webhook.IsPaymentLinkCollection())
+	link, ok := response.Data.FindByCollectionWebhook(&webhook)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), "PL-7F3K2Q9X", link.PaymentLinkID)
+	assert.True(suite.T(), link.IsActive())
+
+	webhook.PaymentLinkID = ""
+	webhook.MerchantReference = "invoice-1002"
+	link, ok = response.Data.FindByCollectionWebhook(&webhook)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), "PL-9Q1W4E7R", link.PaymentLinkID)
+	assert.False(suite.T(), link.IsActive())
+
+	webhook.PaymentLinkID = "PL-UNKNOWN"
+	link, ok = response.Data.FindByCollectionWebhook(&webhook)
+	assert.False(suite.T(), ok)
+	assert.Nil(suite.T(), link)
+}
+
+func TestPaymentLinksTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentLinksTestSuite))
+}
+
+type PaymentLinksResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *PaymentLinksResource
+}
+
+func (suite *PaymentLinksResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &PaymentLinksResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *PaymentLinksResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestCreateSuccess() {
+	body, _ := LoadStubResponseData("stubs/payment-links/create/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/payment-links", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &PaymentLinkRequest{
+		Currency:          CurrencyCodeUGX,
+		Amount:            25000,
+		AllowedMethods:    []TransactionMethodCode{TransactionMethodMobileMoney, TransactionMethodCard},
+		MerchantReference: "invoice-1001",
+		Narration:         "Invoice 1001",
+		CustomerEmail:     "customer@example.com",
+		ExpiresAt:         "2022-07-01 00:00:00",
+	}
+	result, resp, err := suite.testable.Create(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), http.StatusAccepted, result.Code)
+	assert.Equal(suite.T(), "Payment link created", result.Message)
+	assert.Equal(suite.T(), int64(501), result.Data.ID)
+	assert.Equal(suite.T(), "PL-7F3K2Q9X", result.Data.PaymentLinkID)
+	assert.Equal(suite.T(), 25000.0, result.Data.RequestAmount)
+	assert.Equal(suite.T(), "UGX", result.Data.RequestCurrency)
+	assert.Equal(suite.T(), []string{"MOBILE_MONEY", "CARD"}, result.Data.AllowedMethods)
+	assert.Equal(suite.T(), "invoice-1001", result.Data.MerchantReference)
+	assert.Equal(suite.T(), "https://sandbox.dusupay.com/pay/PL-7F3K2Q9X", result.Data.PaymentURL)
+	assert.Equal(suite.T(), "ACTIVE", result.Data.Status)
+	assert.Equal(suite.T(), "2022-07-01 00:00:00", result.Data.ExpiresAt)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestCreateJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/payment-links", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &PaymentLinkRequest{Currency: CurrencyCodeUGX, Amount: 25000, MerchantReference: "invoice-1001", Narration: "Invoice 1001"}
+	result, resp, err := suite.testable.Create(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestCreateNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/payment-links", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &PaymentLinkRequest{Currency: CurrencyCodeUGX, Amount: 25000, MerchantReference: "invoice-1001", Narration: "Invoice 1001"}
+	result, resp, err := suite.testable.Create(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestCreateInvalidRequest() {
+	result, rsp, err := suite.testable.Create(suite.ctx, &PaymentLinkRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestGetListSuccess() {
+	body, _ := LoadStubResponseData("stubs/payment-links/list/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/payment-links", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetList(suite.ctx, &PaymentLinksFilter{Status: PaymentLinkStatusActive})
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Len(suite.T(), *result.Data, 2)
+	assert.Equal(suite.T(), "PL-7F3K2Q9X", (*result.Data)[0].PaymentLinkID)
+	assert.Equal(suite.T(), "EXPIRED", (*result.Data)[1].Status)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestGetListNilFilter() {
+	body, _ := LoadStubResponseData("stubs/payment-links/list/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/payment-links", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, _, err := suite.testable.GetList(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), *result.Data, 2)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestGetListJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/payment-links", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetList(suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestGetSuccess() {
+	body, _ := LoadStubResponseData("stubs/payment-links/get/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/payment-links/PL-7F3K2Q9X", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Get(suite.ctx, "PL-7F3K2Q9X")
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Equal(suite.T(), "PAID", result.Data.Status)
+	assert.False(suite.T(), result.Data.IsActive())
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestGetEmptyId() {
+	result, rsp, err := suite.testable.Get(suite.ctx, "")
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `PaymentLinksResource.Get error: parameter "payment_link_id" is empty`, err.Error())
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestDeactivateSuccess() {
+	body, _ := LoadStubResponseData("stubs/payment-links/deactivate/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/payment-links/PL-7F3K2Q9X/deactivate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Deactivate(suite.ctx, "PL-7F3K2Q9X")
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Equal(suite.T(), "Payment link deactivated", result.Message)
+	assert.Equal(suite.T(), "INACTIVE", result.Data.Status)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestDeactivateNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/payment-links/PL-7F3K2Q9X/deactivate", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Deactivate(suite.ctx, "PL-7F3K2Q9X")
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *PaymentLinksResourceTestSuite) TestDeactivateEmptyId() {
+	result, rsp, err := suite.testable.Deactivate(suite.ctx, "")
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func TestPaymentLinksResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentLinksResourceTestSuite))
+}
@@ -0,0 +1,328 @@
+package dusupay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//PayoutDraftStatusCode type
+type PayoutDraftStatusCode string
+
+//PayoutDraftStatusPendingApproval const
+const PayoutDraftStatusPendingApproval PayoutDraftStatusCode = "PENDING_APPROVAL"
+
+//PayoutDraftStatusSubmitted const
+const PayoutDraftStatusSubmitted PayoutDraftStatusCode = "SUBMITTED"
+
+//PayoutDraftStatusRejected const
+const PayoutDraftStatusRejected PayoutDraftStatusCode = "REJECTED"
+
+//PayoutDraftStatusFailed const (approved but the submission is failed)
+const PayoutDraftStatusFailed PayoutDraftStatusCode = "FAILED"
+
+//PayoutAuditActionCode type
+type PayoutAuditActionCode string
+
+//PayoutAuditActionCreated const
+const PayoutAuditActionCreated PayoutAuditActionCode = "CREATED"
+
+//PayoutAuditActionApproved const
+const PayoutAuditActionApproved PayoutAuditActionCode = "APPROVED"
+
+//PayoutAuditActionRejected const
+const PayoutAuditActionRejected PayoutAuditActionCode = "REJECTED"
+
+//PayoutAuditActionSubmitted const
+const PayoutAuditActionSubmitted PayoutAuditActionCode = "SUBMITTED"
+
+//PayoutAuditActionSubmissionFailed const
+const PayoutAuditActionSubmissionFailed PayoutAuditActionCode = "SUBMISSION_FAILED"
+
+//PayoutDraft payout request held for the approval (identified by the request merchant reference)
+type PayoutDraft struct {
+	ID        string                `json:"id"`
+	Request   *PayoutRequest        `json:"request"`
+	Status    PayoutDraftStatusCode `json:"status"`
+	Maker     string                `json:"maker"`
+	Checker   string                `json:"checker"`
+	CreatedAt time.Time             `json:"created_at"`
+	DecidedAt time.Time             `json:"decided_at"`
+	Comment   string                `json:"comment"`
+	Response  *PayoutResponseData   `json:"response"`
+	LastError string                `json:"last_error"`
+}
+
+//PayoutAuditEntry payout approval audit trail record (who, when, what)
+type PayoutAuditEntry struct {
+	DraftID           string                `json:"draft_id"`
+	Action            PayoutAuditActionCode `json:"action"`
+	Actor             string                `json:"actor"`
+	At                time.Time             `json:"at"`
+	Currency          CurrencyCode          `json:"currency"`
+	Amount            float64               `json:"amount"`
+	AccountNumber     string                `json:"account_number"`
+	MerchantReference string                `json:"merchant_reference"`
+	Comment           string                `json:"comment"`
+}
+
+//PayoutApproverInterface approval authority interface (decides is actor allowed to approve the draft)
+type PayoutApproverInterface interface {
+	CanApprove(ctx context.Context, actor string, draft *PayoutDraft) (bool, error)
+}
+
+//PayoutDraftStorageInterface payout drafts storage interface
+type PayoutDraftStorageInterface interface {
+	Save(draft *PayoutDraft) error
+	Get(id string) (*PayoutDraft, error)
+}
+
+//PayoutAuditLogInterface payout approval audit log interface
+type PayoutAuditLogInterface interface {
+	Record(entry *PayoutAuditEntry) error
+}
+
+//NewPayoutDraftMemoryStorage create new in-memory payout drafts storage
+func NewPayoutDraftMemoryStorage() *PayoutDraftMemoryStorage {
+	return &PayoutDraftMemoryStorage{drafts: make(map[string]PayoutDraft)}
+}
+
+//PayoutDraftMemoryStorage in-memory payout drafts storage
+type PayoutDraftMemoryStorage struct {
+	mu     sync.RWMutex
+	drafts map[string]PayoutDraft
+}
+
+//Save save payout draft
+func (ms *PayoutDraftMemoryStorage) Save(draft *PayoutDraft) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	d := *draft
+	d.Request = copyPayoutRequest(draft.Request)
+	ms.drafts[draft.ID] = d
+	return nil
+}
+
+//Get get payout draft by id
+func (ms *PayoutDraftMemoryStorage) Get(id string) (*PayoutDraft, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	draft, ok := ms.drafts[id]
+	if !ok {
+		return nil, fmt.Errorf(`payout draft "%s" not found`, id)
+	}
+	draft.Request = copyPayoutRequest(draft.Request)
+	return &draft, nil
+}
+
+//NewPayoutAuditMemoryLog create new in-memory payout audit log
+func NewPayoutAuditMemoryLog() *PayoutAuditMemoryLog {
+	return &PayoutAuditMemoryLog{}
+}
+
+//PayoutAuditMemoryLog in-memory payout audit log
+type PayoutAuditMemoryLog struct {
+	mu      sync.RWMutex
+	entries []PayoutAuditEntry
+}
+
+//Record append audit entry
+func (ml *PayoutAuditMemoryLog) Record(entry *PayoutAuditEntry) error {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	ml.entries = append(ml.entries, *entry)
+	return nil
+}
+
+//Entries get audit entries of the draft (all entries when draft id is empty)
+func (ml *PayoutAuditMemoryLog) Entries(draftId string) []PayoutAuditEntry {
+	ml.mu.RLock()
+	defer ml.mu.RUnlock()
+	var entries []PayoutAuditEntry
+	for _, entry := range ml.entries {
+		if draftId == "" || entry.DraftID == draftId {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+//NewPayoutApprovalWorkflow create new maker-checker payout approval workflow
+func NewPayoutApprovalWorkflow(payouts PayoutCreatorInterface, approver PayoutApproverInterface, storage PayoutDraftStorageInterface, audit PayoutAuditLogInterface) *PayoutApprovalWorkflow {
+	return &PayoutApprovalWorkflow{
+		Thresholds: make(map[CurrencyCode]float64),
+		Now:        time.Now,
+		payouts:    payouts,
+		approver:   approver,
+		storage:    storage,
+		audit:      audit,
+	}
+}
+
+//PayoutApprovalWorkflow maker-checker approval layer in front of the payouts creation
+type PayoutApprovalWorkflow struct {
+	//payouts above the currency threshold require the approval (currencies without threshold are submitted directly)
+	Thresholds map[CurrencyCode]float64
+	//clock used for the audit trail
+	Now      func() time.Time
+	mu       sync.Mutex
+	payouts  PayoutCreatorInterface
+	approver PayoutApproverInterface
+	storage  PayoutDraftStorageInterface
+	audit    PayoutAuditLogInterface
+}
+
+//SetThreshold set currency approval threshold
+func (pw *PayoutApprovalWorkflow) SetThreshold(currency CurrencyCode, amount float64) *PayoutApprovalWorkflow {
+	pw.Thresholds[currency] = amount
+	return pw
+}
+
+//RequiresApproval check is payout request amount above the currency threshold
+func (pw *PayoutApprovalWorkflow) RequiresApproval(req *PayoutRequest) bool {
+	threshold, ok := pw.Thresholds[req.Currency]
+	return ok && req.Amount > threshold
+}
+
+//Submit submit payout request on behalf of the maker, payouts above the threshold are held as drafts (nil response)
+func (pw *PayoutApprovalWorkflow) Submit(ctx context.Context, req *PayoutRequest, maker string) (*PayoutDraft, *PayoutResponse, error) {
+	if maker == "" {
+		return nil, nil, fmt.Errorf(`PayoutApprovalWorkflow.Submit error: parameter "maker" is empty`)
+	}
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutApprovalWorkflow.Submit error: %v", err)
+	}
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if _, err := pw.storage.Get(req.MerchantReference); err == nil {
+		return nil, nil, fmt.Errorf(`PayoutApprovalWorkflow.Submit error: payout draft "%s" already exists`, req.MerchantReference)
+	}
+	//the draft keeps its own request copy, so the maker can not change it after the submission
+	draft := &PayoutDraft{
+		ID:        req.MerchantReference,
+		Request:   copyPayoutRequest(req),
+		Status:    PayoutDraftStatusPendingApproval,
+		Maker:     maker,
+		CreatedAt: pw.Now(),
+	}
+	err = pw.save(draft, PayoutAuditActionCreated, maker, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutApprovalWorkflow.Submit error: %v", err)
+	}
+	if pw.RequiresApproval(draft.Request) {
+		return draft, nil, nil
+	}
+	rsp, err := pw.submit(ctx, draft, maker)
+	return draft, rsp, err
+}
+
+//Approve approve pending draft by the checker (must be different from the maker) and submit the payout
+func (pw *PayoutApprovalWorkflow) Approve(ctx context.Context, draftId string, checker string, comment string) (*PayoutDraft, *PayoutResponse, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	draft, err := pw.getPending(draftId, checker)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutApprovalWorkflow.Approve error: %v", err)
+	}
+	err = draft.Request.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf(`PayoutApprovalWorkflow.Approve error: payout draft "%s": %v`, draftId, err)
+	}
+	if !pw.RequiresApproval(draft.Request) {
+		return nil, nil, fmt.Errorf(`PayoutApprovalWorkflow.Approve error: payout draft "%s" does not require approval`, draftId)
+	}
+	allowed, err := pw.approver.CanApprove(ctx, checker, draft)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutApprovalWorkflow.Approve error: %v", err)
+	}
+	if !allowed {
+		return nil, nil, fmt.Errorf(`PayoutApprovalWorkflow.Approve error: actor "%s" is not allowed to approve payout draft "%s"`, checker, draftId)
+	}
+	draft.Checker = checker
+	draft.DecidedAt = pw.Now()
+	draft.Comment = comment
+	err = pw.save(draft, PayoutAuditActionApproved, checker, comment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutApprovalWorkflow.Approve error: %v", err)
+	}
+	rsp, err := pw.submit(ctx, draft, checker)
+	return draft, rsp, err
+}
+
+//Reject reject pending draft by the checker (must be different from the maker)
+func (pw *PayoutApprovalWorkflow) Reject(draftId string, checker string, reason string) (*PayoutDraft, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	draft, err := pw.getPending(draftId, checker)
+	if err != nil {
+		return nil, fmt.Errorf("PayoutApprovalWorkflow.Reject error: %v", err)
+	}
+	draft.Status = PayoutDraftStatusRejected
+	draft.Checker = checker
+	draft.DecidedAt = pw.Now()
+	draft.Comment = reason
+	err = pw.save(draft, PayoutAuditActionRejected, checker, reason)
+	if err != nil {
+		return nil, fmt.Errorf("PayoutApprovalWorkflow.Reject error: %v", err)
+	}
+	return draft, nil
+}
+
+//getPending get pending draft and check the checker is not the maker
+func (pw *PayoutApprovalWorkflow) getPending(draftId string, checker string) (*PayoutDraft, error) {
+	if checker == "" {
+		return nil, fmt.Errorf(`parameter "checker" is empty`)
+	}
+	draft, err := pw.storage.Get(draftId)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != PayoutDraftStatusPendingApproval {
+		return nil, fmt.Errorf(`payout draft "%s" is already "%s"`, draftId, draft.Status)
+	}
+	if draft.Maker == checker {
+		return nil, fmt.Errorf(`payout draft "%s" can not be decided by its maker "%s"`, draftId, checker)
+	}
+	return draft, nil
+}
+
+//submit create payout and record the result
+func (pw *PayoutApprovalWorkflow) submit(ctx context.Context, draft *PayoutDraft, actor string) (*PayoutResponse, error) {
+	rsp, _, err := pw.payouts.Create(ctx, draft.Request)
+	if err != nil {
+		draft.Status = PayoutDraftStatusFailed
+		draft.LastError = err.Error()
+		if e := pw.save(draft, PayoutAuditActionSubmissionFailed, actor, err.Error()); e != nil {
+			return rsp, fmt.Errorf("PayoutApprovalWorkflow.submit error: %v", e)
+		}
+		return rsp, err
+	}
+	draft.Status = PayoutDraftStatusSubmitted
+	draft.Response = rsp.Data
+	err = pw.save(draft, PayoutAuditActionSubmitted, actor, "")
+	if err != nil {
+		return rsp, fmt.Errorf("PayoutApprovalWorkflow.submit error: %v", err)
+	}
+	return rsp, nil
+}
+
+//save save draft and record the audit entry
+func (pw *PayoutApprovalWorkflow) save(draft *PayoutDraft, action PayoutAuditActionCode, actor string, comment string) error {
+	err := pw.storage.Save(draft)
+	if err != nil {
+		return err
+	}
+	return pw.audit.Record(&PayoutAuditEntry{
+		DraftID:           draft.ID,
+		Action:            action,
+		Actor:             actor,
+		At:                pw.Now(),
+		Currency:          draft.Request.Currency,
+		Amount:            draft.Request.Amount,
+		AccountNumber:     draft.Request.AccountNumber,
+		MerchantReference: draft.Request.MerchantReference,
+		Comment:           comment,
+	})
+}
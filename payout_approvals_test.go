@@ -0,0 +1,167 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type stubPayoutApprover struct {
+	approvers map[string]bool
+	err       error
+}
+
+func (s *stubPayoutApprover) CanApprove(ctx context.Context, actor string, draft *PayoutDraft) (bool, error) {
+	return s.approvers[actor], s.err
+}
+
+type PayoutApprovalWorkflowTestSuite struct {
+	suite.Suite
+	now      time.Time
+	ctx      context.Context
+	payouts  *stubPayoutCreator
+	approver *stubPayoutApprover
+	audit    *PayoutAuditMemoryLog
+	testable *PayoutApprovalWorkflow
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) SetupTest() {
+	suite.now = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	suite.ctx = context.Background()
+	suite.payouts = &stubPayoutCreator{}
+	suite.approver = &stubPayoutApprover{approvers: map[string]bool{"checker": true, "maker": true}}
+	suite.audit = NewPayoutAuditMemoryLog()
+	suite.testable = NewPayoutApprovalWorkflow(suite.payouts, suite.approver, NewPayoutDraftMemoryStorage(), suite.audit)
+	suite.testable.Now = func() time.Time {
+		return suite.now
+	}
+	suite.testable.SetThreshold(CurrencyCodeUGX, 100000)
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestSubmitBelowThreshold() {
+	draft, rsp, err := suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 100000), "maker")
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), rsp)
+	assert.Equal(suite.T(), PayoutDraftStatusSubmitted, draft.Status)
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+	entries := suite.audit.Entries("r1")
+	assert.Len(suite.T(), entries, 2)
+	assert.Equal(suite.T(), PayoutAuditActionCreated, entries[0].Action)
+	assert.Equal(suite.T(), PayoutAuditActionSubmitted, entries[1].Action)
+	assert.Equal(suite.T(), "maker", entries[1].Actor)
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestSubmitWithoutCurrencyThreshold() {
+	req := buildStubScheduledPayoutRequest("r1", 1000)
+	req.Currency = CurrencyCodeUSD
+	assert.False(suite.T(), suite.testable.RequiresApproval(req))
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestSubmitInvalid() {
+	_, _, err := suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 100), "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Submit error: parameter "maker" is empty`, err.Error())
+	_, _, err = suite.testable.Submit(suite.ctx, &PayoutRequest{}, "maker")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Submit error: parameter "currency" is empty`, err.Error())
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestSubmitDuplicate() {
+	_, _, _ = suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	_, _, err := suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Submit error: payout draft "r1" already exists`, err.Error())
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestApproveAboveThreshold() {
+	draft, rsp, err := suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), rsp)
+	assert.Equal(suite.T(), PayoutDraftStatusPendingApproval, draft.Status)
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+
+	suite.now = suite.now.Add(time.Hour)
+	draft, rsp, err = suite.testable.Approve(suite.ctx, "r1", "checker", "ok")
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), rsp)
+	assert.Equal(suite.T(), PayoutDraftStatusSubmitted, draft.Status)
+	assert.Equal(suite.T(), "checker", draft.Checker)
+	assert.Equal(suite.T(), suite.now, draft.DecidedAt)
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+
+	entries := suite.audit.Entries("r1")
+	assert.Len(suite.T(), entries, 3)
+	assert.Equal(suite.T(), PayoutAuditActionApproved, entries[1].Action)
+	assert.Equal(suite.T(), "checker", entries[1].Actor)
+	assert.Equal(suite.T(), "ok", entries[1].Comment)
+	assert.Equal(suite.T(), 500000.0, entries[1].Amount)
+	assert.Equal(suite.T(), suite.now, entries[1].At)
+
+	_, _, err = suite.testable.Approve(suite.ctx, "r1", "checker", "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Approve error: payout draft "r1" is already "SUBMITTED"`, err.Error())
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestApproveIgnoresRequestChangedAfterSubmit() {
+	req := buildStubScheduledPayoutRequest("r1", 500000)
+	draft, _, _ := suite.testable.Submit(suite.ctx, req, "maker")
+	req.Amount = 9999999
+	req.AccountNumber = "256700000999"
+	draft.Request.Amount = 9999999
+
+	_, _, err := suite.testable.Approve(suite.ctx, "r1", "checker", "")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+	assert.Equal(suite.T(), 500000.0, suite.payouts.requests[0].Amount)
+	assert.NotEqual(suite.T(), "256700000999", suite.payouts.requests[0].AccountNumber)
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestApproveBelowThreshold() {
+	_, _, _ = suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	suite.testable.SetThreshold(CurrencyCodeUGX, 1000000)
+	_, _, err := suite.testable.Approve(suite.ctx, "r1", "checker", "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Approve error: payout draft "r1" does not require approval`, err.Error())
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestApproveBySameActor() {
+	_, _, _ = suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	_, _, err := suite.testable.Approve(suite.ctx, "r1", "maker", "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Approve error: payout draft "r1" can not be decided by its maker "maker"`, err.Error())
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestApproveNotAllowed() {
+	_, _, _ = suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	_, _, err := suite.testable.Approve(suite.ctx, "r1", "intern", "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Approve error: actor "intern" is not allowed to approve payout draft "r1"`, err.Error())
+	suite.approver.err = errors.New("directory unavailable")
+	_, _, err = suite.testable.Approve(suite.ctx, "r1", "checker", "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Approve error: directory unavailable`, err.Error())
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestApproveSubmissionFailed() {
+	suite.payouts.err = errors.New("Insufficient balance")
+	_, _, _ = suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	draft, _, err := suite.testable.Approve(suite.ctx, "r1", "checker", "")
+	assert.Equal(suite.T(), "Insufficient balance", err.Error())
+	assert.Equal(suite.T(), PayoutDraftStatusFailed, draft.Status)
+	entries := suite.audit.Entries("r1")
+	assert.Equal(suite.T(), PayoutAuditActionSubmissionFailed, entries[2].Action)
+}
+
+func (suite *PayoutApprovalWorkflowTestSuite) TestReject() {
+	_, _, _ = suite.testable.Submit(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000), "maker")
+	draft, err := suite.testable.Reject("r1", "checker", "wrong beneficiary")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), PayoutDraftStatusRejected, draft.Status)
+	assert.Equal(suite.T(), "wrong beneficiary", draft.Comment)
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+	assert.Equal(suite.T(), PayoutAuditActionRejected, suite.audit.Entries("r1")[1].Action)
+
+	_, err = suite.testable.Reject("unknown", "checker", "")
+	assert.Equal(suite.T(), `PayoutApprovalWorkflow.Reject error: payout draft "unknown" not found`, err.Error())
+}
+
+func TestPayoutApprovalWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutApprovalWorkflowTestSuite))
+}
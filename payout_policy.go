@@ -0,0 +1,411 @@
+package dusupay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//PayoutPolicyViolationCode type
+type PayoutPolicyViolationCode string
+
+//PayoutPolicyViolationTransactionLimit const
+const PayoutPolicyViolationTransactionLimit PayoutPolicyViolationCode = "TRANSACTION_LIMIT"
+
+//PayoutPolicyViolationBeneficiaryLimit const
+const PayoutPolicyViolationBeneficiaryLimit PayoutPolicyViolationCode = "BENEFICIARY_LIMIT"
+
+//PayoutPolicyViolationDailyLimit const
+const PayoutPolicyViolationDailyLimit PayoutPolicyViolationCode = "DAILY_LIMIT"
+
+//PayoutPolicyViolationCurrencyNotAllowed const
+const PayoutPolicyViolationCurrencyNotAllowed PayoutPolicyViolationCode = "CURRENCY_NOT_ALLOWED"
+
+//PayoutPolicyViolationVelocity const
+const PayoutPolicyViolationVelocity PayoutPolicyViolationCode = "VELOCITY"
+
+//PayoutPolicyViolationBlocked const
+const PayoutPolicyViolationBlocked PayoutPolicyViolationCode = "BLOCKED"
+
+//PayoutPolicyViolationError payout rejected by the policy
+type PayoutPolicyViolationError struct {
+	Code          PayoutPolicyViolationCode
+	Currency      CurrencyCode
+	AccountNumber string
+	//violated limit (amount or payouts count)
+	Limit float64
+	//value the payout would reach (amount or payouts count)
+	Attempted float64
+	Message   string
+}
+
+//Error error interface implementation
+func (e *PayoutPolicyViolationError) Error() string {
+	return fmt.Sprintf("payout policy violation %s: %s", e.Code, e.Message)
+}
+
+//PayoutLimits currency payout limits (zero value disables the limit)
+type PayoutLimits struct {
+	PerTransaction float64 `json:"per_transaction"`
+	//total amount per beneficiary account per day
+	PerBeneficiary float64 `json:"per_beneficiary"`
+	//total amount per day
+	PerDay float64 `json:"per_day"`
+}
+
+//PayoutVelocityRule max payouts count to the same account number within the window
+type PayoutVelocityRule struct {
+	MaxCount int           `json:"max_count"`
+	Window   time.Duration `json:"window"`
+}
+
+//PayoutPolicy payout safeguards
+type PayoutPolicy struct {
+	//per-currency limits, currencies without limits are rejected when StrictCurrencies is set
+	Limits           map[CurrencyCode]*PayoutLimits
+	StrictCurrencies bool
+	Velocity         *PayoutVelocityRule
+	//blocked beneficiary account numbers
+	BlockedAccounts map[string]bool
+	//blocked provider ids
+	BlockedProviders map[string]bool
+	//location of the day boundaries for the daily limits
+	Location *time.Location
+}
+
+//NewPayoutPolicy create new empty payout policy
+func NewPayoutPolicy() *PayoutPolicy {
+	return &PayoutPolicy{
+		Limits:           make(map[CurrencyCode]*PayoutLimits),
+		BlockedAccounts:  make(map[string]bool),
+		BlockedProviders: make(map[string]bool),
+		Location:         time.UTC,
+	}
+}
+
+//SetLimits set currency payout limits
+func (pp *PayoutPolicy) SetLimits(currency CurrencyCode, limits *PayoutLimits) *PayoutPolicy {
+	pp.Limits[currency] = limits
+	return pp
+}
+
+//SetVelocity set velocity rule
+func (pp *PayoutPolicy) SetVelocity(maxCount int, window time.Duration) *PayoutPolicy {
+	pp.Velocity = &PayoutVelocityRule{MaxCount: maxCount, Window: window}
+	return pp
+}
+
+//BlockAccount add beneficiary account number to the blocklist (mobile numbers in the international format)
+func (pp *PayoutPolicy) BlockAccount(accountNumber string) *PayoutPolicy {
+	pp.BlockedAccounts[normalizePolicyAccount(accountNumber)] = true
+	return pp
+}
+
+//BlockMobileAccount add beneficiary mobile number in any format (0772..., +256 772...) to the blocklist
+func (pp *PayoutPolicy) BlockMobileAccount(accountNumber string, country CountryCode) error {
+	m, err := ParseMSISDN(accountNumber, country)
+	if err != nil {
+		return fmt.Errorf("PayoutPolicy.BlockMobileAccount error: %v", err)
+	}
+	pp.BlockedAccounts[m.AccountNumber()] = true
+	return nil
+}
+
+//BlockProvider add provider id to the blocklist
+func (pp *PayoutPolicy) BlockProvider(providerId string) *PayoutPolicy {
+	pp.BlockedProviders[providerId] = true
+	return pp
+}
+
+//PayoutCounterStoreInterface payout counters store interface
+type PayoutCounterStoreInterface interface {
+	//Add register payout amount under the counter key
+	Add(key string, amount float64, at time.Time) error
+	//Sum get total amount and count registered under the counter key since the time
+	Sum(key string, since time.Time) (float64, int, error)
+	//Release remove payout amount registered under the counter key at the time
+	Release(key string, amount float64, at time.Time) error
+}
+
+//PayoutCounterRetentionInterface optional counters store interface to keep events for the policy windows (e.g. velocity window)
+type PayoutCounterRetentionInterface interface {
+	EnsureRetention(retention time.Duration)
+}
+
+//MinPayoutCounterRetention min counters retention (daily and beneficiary limits sum events since the day start)
+const MinPayoutCounterRetention = 24 * time.Hour
+
+//NewPayoutCounterMemoryStore create new in-memory payout counters store
+//(events older than retention are pruned, retention is at least MinPayoutCounterRetention, zero retention disables pruning)
+func NewPayoutCounterMemoryStore(retention time.Duration) *PayoutCounterMemoryStore {
+	if retention > 0 && retention < MinPayoutCounterRetention {
+		retention = MinPayoutCounterRetention
+	}
+	return &PayoutCounterMemoryStore{retention: retention, events: make(map[string][]payoutCounterEvent)}
+}
+
+//payoutCounterEvent registered payout
+type payoutCounterEvent struct {
+	amount float64
+	at     time.Time
+}
+
+//PayoutCounterMemoryStore in-memory payout counters store
+type PayoutCounterMemoryStore struct {
+	mu        sync.Mutex
+	retention time.Duration
+	events    map[string][]payoutCounterEvent
+}
+
+//Add register payout amount under the counter key
+func (ms *PayoutCounterMemoryStore) Add(key string, amount float64, at time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	events := ms.events[key]
+	if ms.retention > 0 {
+		threshold := at.Add(-ms.retention)
+		kept := events[:0]
+		for _, event := range events {
+			if !event.at.Before(threshold) {
+				kept = append(kept, event)
+			}
+		}
+		events = kept
+	}
+	ms.events[key] = append(events, payoutCounterEvent{amount: amount, at: at})
+	return nil
+}
+
+//EnsureRetention extend retention to keep events for the window
+func (ms *PayoutCounterMemoryStore) EnsureRetention(retention time.Duration) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.retention > 0 && ms.retention < retention {
+		ms.retention = retention
+	}
+}
+
+//Sum get total amount and count registered under the counter key since the time
+func (ms *PayoutCounterMemoryStore) Sum(key string, since time.Time) (float64, int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var total float64
+	var count int
+	for _, event := range ms.events[key] {
+		if !event.at.Before(since) {
+			total += event.amount
+			count++
+		}
+	}
+	return roundAmount(total), count, nil
+}
+
+//Release remove payout amount registered under the counter key at the time
+func (ms *PayoutCounterMemoryStore) Release(key string, amount float64, at time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	events := ms.events[key]
+	for i, event := range events {
+		if event.amount == amount && event.at.Equal(at) {
+			ms.events[key] = append(events[:i], events[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+//NewPayoutPolicyEnforcer create new payout policy enforcer in front of the payouts creator
+func NewPayoutPolicyEnforcer(payouts PayoutCreatorInterface, policy *PayoutPolicy, counters PayoutCounterStoreInterface) *PayoutPolicyEnforcer {
+	return &PayoutPolicyEnforcer{Now: time.Now, payouts: payouts, policy: policy, counters: counters}
+}
+
+//PayoutPolicyEnforcer payout policy layer (implements PayoutCreatorInterface, so it can be used by the schedulers and workflows)
+type PayoutPolicyEnforcer struct {
+	Now      func() time.Time
+	mu       sync.Mutex
+	payouts  PayoutCreatorInterface
+	policy   *PayoutPolicy
+	counters PayoutCounterStoreInterface
+}
+
+//Check evaluate payout request against the policy
+func (pe *PayoutPolicyEnforcer) Check(req *PayoutRequest) error {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	return pe.check(req, pe.Now())
+}
+
+//Create evaluate payout request against the policy and create it (checks and creations are serialized).
+//The amount is reserved in the counters before the creation and released only when the API definitely rejects the payout,
+//so timeouts and unparseable responses of the accepted payouts are still counted.
+func (pe *PayoutPolicyEnforcer) Create(ctx context.Context, req *PayoutRequest) (*PayoutResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutPolicyEnforcer.Create error: %v", err)
+	}
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	now := pe.Now()
+	err = pe.check(req, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = pe.record(req, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PayoutPolicyEnforcer.Create error: %v", err)
+	}
+	result, rsp, err := pe.payouts.Create(ctx, req)
+	if err != nil && result != nil && isRejectedResponse(&result.ResponseBody) {
+		if e := pe.release(req, now); e != nil {
+			return result, rsp, fmt.Errorf("PayoutPolicyEnforcer.Create error: %v", e)
+		}
+	}
+	return result, rsp, err
+}
+
+//check evaluate payout request against the policy
+func (pe *PayoutPolicyEnforcer) check(req *PayoutRequest, now time.Time) error {
+	account := normalizePayoutAccount(req)
+	if pe.policy.BlockedAccounts[account] {
+		return &PayoutPolicyViolationError{Code: PayoutPolicyViolationBlocked, Currency: req.Currency, AccountNumber: req.AccountNumber,
+			Message: fmt.Sprintf(`account "%s" is blocked`, req.AccountNumber)}
+	}
+	if pe.policy.BlockedProviders[req.ProviderId] {
+		return &PayoutPolicyViolationError{Code: PayoutPolicyViolationBlocked, Currency: req.Currency, AccountNumber: req.AccountNumber,
+			Message: fmt.Sprintf(`provider "%s" is blocked`, req.ProviderId)}
+	}
+	limits, ok := pe.policy.Limits[req.Currency]
+	if !ok && pe.policy.StrictCurrencies {
+		return &PayoutPolicyViolationError{Code: PayoutPolicyViolationCurrencyNotAllowed, Currency: req.Currency, AccountNumber: req.AccountNumber,
+			Message: fmt.Sprintf(`currency "%s" is not allowed`, req.Currency)}
+	}
+	if ok {
+		if limits.PerTransaction > 0 && req.Amount > limits.PerTransaction {
+			return &PayoutPolicyViolationError{Code: PayoutPolicyViolationTransactionLimit, Currency: req.Currency, AccountNumber: req.AccountNumber,
+				Limit: limits.PerTransaction, Attempted: req.Amount,
+				Message: fmt.Sprintf(`amount %v exceeds "%s" transaction limit %v`, req.Amount, req.Currency, limits.PerTransaction)}
+		}
+		dayStart := pe.dayStart(now)
+		if limits.PerBeneficiary > 0 {
+			total, _, err := pe.counters.Sum(buildPayoutBeneficiaryCounterKey(req.Currency, account), dayStart)
+			if err != nil {
+				return err
+			}
+			if attempted := roundAmount(total + req.Amount); attempted > limits.PerBeneficiary {
+				return &PayoutPolicyViolationError{Code: PayoutPolicyViolationBeneficiaryLimit, Currency: req.Currency, AccountNumber: req.AccountNumber,
+					Limit: limits.PerBeneficiary, Attempted: attempted,
+					Message: fmt.Sprintf(`account "%s" daily amount %v exceeds "%s" beneficiary limit %v`, req.AccountNumber, attempted, req.Currency, limits.PerBeneficiary)}
+			}
+		}
+		if limits.PerDay > 0 {
+			total, _, err := pe.counters.Sum(buildPayoutDailyCounterKey(req.Currency), dayStart)
+			if err != nil {
+				return err
+			}
+			if attempted := roundAmount(total + req.Amount); attempted > limits.PerDay {
+				return &PayoutPolicyViolationError{Code: PayoutPolicyViolationDailyLimit, Currency: req.Currency, AccountNumber: req.AccountNumber,
+					Limit: limits.PerDay, Attempted: attempted,
+					Message: fmt.Sprintf(`daily amount %v exceeds "%s" daily limit %v`, attempted, req.Currency, limits.PerDay)}
+			}
+		}
+	}
+	if pe.policy.Velocity != nil && pe.policy.Velocity.MaxCount > 0 {
+		if store, ok := pe.counters.(PayoutCounterRetentionInterface); ok {
+			store.EnsureRetention(pe.policy.Velocity.Window)
+		}
+		_, count, err := pe.counters.Sum(buildPayoutVelocityCounterKey(account), now.Add(-pe.policy.Velocity.Window))
+		if err != nil {
+			return err
+		}
+		if count+1 > pe.policy.Velocity.MaxCount {
+			return &PayoutPolicyViolationError{Code: PayoutPolicyViolationVelocity, Currency: req.Currency, AccountNumber: req.AccountNumber,
+				Limit: float64(pe.policy.Velocity.MaxCount), Attempted: float64(count + 1),
+				Message: fmt.Sprintf(`account "%s" exceeds %d payouts per %v`, req.AccountNumber, pe.policy.Velocity.MaxCount, pe.policy.Velocity.Window)}
+		}
+	}
+	return nil
+}
+
+//record reserve payout amount in the counters
+func (pe *PayoutPolicyEnforcer) record(req *PayoutRequest, now time.Time) error {
+	for _, key := range pe.counterKeys(req) {
+		err := pe.counters.Add(key, req.Amount, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//release remove rejected payout amount from the counters
+func (pe *PayoutPolicyEnforcer) release(req *PayoutRequest, now time.Time) error {
+	for _, key := range pe.counterKeys(req) {
+		err := pe.counters.Release(key, req.Amount, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//counterKeys get payout counters keys
+func (pe *PayoutPolicyEnforcer) counterKeys(req *PayoutRequest) []string {
+	account := normalizePayoutAccount(req)
+	return []string{
+		buildPayoutBeneficiaryCounterKey(req.Currency, account),
+		buildPayoutDailyCounterKey(req.Currency),
+		buildPayoutVelocityCounterKey(account),
+	}
+}
+
+//dayStart get start of the policy day
+func (pe *PayoutPolicyEnforcer) dayStart(now time.Time) time.Time {
+	location := pe.policy.Location
+	if location == nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+}
+
+//normalizePayoutAccount normalize payout beneficiary account, mobile numbers are parsed by the provider country rules
+func normalizePayoutAccount(req *PayoutRequest) string {
+	if req.Method == TransactionMethodMobileMoney {
+		if m, err := ParseMSISDN(req.AccountNumber, providerCountry(req.ProviderId)); err == nil {
+			return m.AccountNumber()
+		}
+	}
+	return normalizePolicyAccount(req.AccountNumber)
+}
+
+//normalizePolicyAccount normalize account number to digits only (accounts with letters, e.g. crypto wallets, are lowercased)
+func normalizePolicyAccount(accountNumber string) string {
+	accountNumber = strings.TrimSpace(accountNumber)
+	var sb strings.Builder
+	for _, r := range accountNumber {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		} else if !strings.ContainsRune(" +-.()", r) {
+			return strings.ToLower(accountNumber)
+		}
+	}
+	return sb.String()
+}
+
+//BuildPayoutBeneficiaryCounterKey func
+func buildPayoutBeneficiaryCounterKey(currency CurrencyCode, account string) string {
+	return fmt.Sprintf("beneficiary:%s:%s", currency, account)
+}
+
+//BuildPayoutDailyCounterKey func
+func buildPayoutDailyCounterKey(currency CurrencyCode) string {
+	return fmt.Sprintf("daily:%s", currency)
+}
+
+//BuildPayoutVelocityCounterKey func
+func buildPayoutVelocityCounterKey(account string) string {
+	return fmt.Sprintf("velocity:%s", account)
+}
@@ -0,0 +1,270 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type PayoutPolicyEnforcerTestSuite struct {
+	suite.Suite
+	now      time.Time
+	ctx      context.Context
+	payouts  *stubPayoutCreator
+	policy   *PayoutPolicy
+	testable *PayoutPolicyEnforcer
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) SetupTest() {
+	suite.now = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	suite.ctx = context.Background()
+	suite.payouts = &stubPayoutCreator{}
+	suite.policy = NewPayoutPolicy().
+		SetLimits(CurrencyCodeUGX, &PayoutLimits{PerTransaction: 500000, PerBeneficiary: 700000, PerDay: 1000000})
+	suite.testable = NewPayoutPolicyEnforcer(suite.payouts, suite.policy, NewPayoutCounterMemoryStore(48*time.Hour))
+	suite.testable.Now = func() time.Time {
+		return suite.now
+	}
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) assertViolation(err error, code PayoutPolicyViolationCode) *PayoutPolicyViolationError {
+	var violation *PayoutPolicyViolationError
+	assert.True(suite.T(), errors.As(err, &violation))
+	assert.Equal(suite.T(), code, violation.Code)
+	return violation
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestCreateSuccess() {
+	rsp, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 100000))
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), rsp)
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestTransactionLimit() {
+	_, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 500001))
+	violation := suite.assertViolation(err, PayoutPolicyViolationTransactionLimit)
+	assert.Equal(suite.T(), 500000.0, violation.Limit)
+	assert.Equal(suite.T(), 500001.0, violation.Attempted)
+	assert.Equal(suite.T(), `payout policy violation TRANSACTION_LIMIT: amount 500001 exceeds "UGX" transaction limit 500000`, err.Error())
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestBeneficiaryLimit() {
+	_, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 400000))
+	assert.NoError(suite.T(), err)
+	req := buildStubScheduledPayoutRequest("r2", 400000)
+	req.AccountNumber = "+256777111786"
+	_, _, err = suite.testable.Create(suite.ctx, req)
+	violation := suite.assertViolation(err, PayoutPolicyViolationBeneficiaryLimit)
+	assert.Equal(suite.T(), 800000.0, violation.Attempted)
+
+	//limits are reset on the next day
+	suite.now = suite.now.Add(24 * time.Hour)
+	_, _, err = suite.testable.Create(suite.ctx, req)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestDailyLimit() {
+	for i, account := range []string{"256777000001", "256777000002"} {
+		req := buildStubScheduledPayoutRequest("r"+account, 450000)
+		req.AccountNumber = account
+		_, _, err := suite.testable.Create(suite.ctx, req)
+		assert.NoError(suite.T(), err, i)
+	}
+	req := buildStubScheduledPayoutRequest("r3", 150000)
+	req.AccountNumber = "256777000003"
+	err := suite.testable.Check(req)
+	violation := suite.assertViolation(err, PayoutPolicyViolationDailyLimit)
+	assert.Equal(suite.T(), 1000000.0, violation.Limit)
+	assert.Equal(suite.T(), 1050000.0, violation.Attempted)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestStrictCurrencies() {
+	req := buildStubScheduledPayoutRequest("r1", 10)
+	req.Currency = CurrencyCodeUSD
+	assert.NoError(suite.T(), suite.testable.Check(req))
+	suite.policy.StrictCurrencies = true
+	suite.assertViolation(suite.testable.Check(req), PayoutPolicyViolationCurrencyNotAllowed)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestDailyLimitWithShortRetention() {
+	suite.now = time.Date(2022, 6, 1, 1, 0, 0, 0, time.UTC)
+	suite.testable = NewPayoutPolicyEnforcer(suite.payouts, suite.policy, NewPayoutCounterMemoryStore(time.Hour))
+	suite.testable.Now = func() time.Time {
+		return suite.now
+	}
+	for i, account := range []string{"256777111781", "256777111782"} {
+		req := buildStubScheduledPayoutRequest(fmt.Sprintf("r%d", i), 450000)
+		req.AccountNumber = account
+		_, _, err := suite.testable.Create(suite.ctx, req)
+		assert.NoError(suite.T(), err)
+		suite.now = suite.now.Add(5 * time.Hour)
+	}
+	//the first payout of the day is still counted
+	req := buildStubScheduledPayoutRequest("r3", 200000)
+	req.AccountNumber = "256777111783"
+	_, _, err := suite.testable.Create(suite.ctx, req)
+	violation := suite.assertViolation(err, PayoutPolicyViolationDailyLimit)
+	assert.Equal(suite.T(), 1100000.0, violation.Attempted)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestVelocityWindowLongerThanRetention() {
+	suite.policy.SetVelocity(2, 72*time.Hour)
+	_, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 100))
+	assert.NoError(suite.T(), err)
+	//the counters store retention (48h) is extended to the velocity window, so the first payout is not pruned
+	suite.now = suite.now.Add(50 * time.Hour)
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r2", 100))
+	assert.NoError(suite.T(), err)
+	suite.now = suite.now.Add(10 * time.Hour)
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r3", 100))
+	violation := suite.assertViolation(err, PayoutPolicyViolationVelocity)
+	assert.Equal(suite.T(), 3.0, violation.Attempted)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestVelocity() {
+	suite.policy.SetVelocity(2, time.Hour)
+	_, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 100))
+	assert.NoError(suite.T(), err)
+	suite.now = suite.now.Add(10 * time.Minute)
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r2", 100))
+	assert.NoError(suite.T(), err)
+	suite.now = suite.now.Add(10 * time.Minute)
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r3", 100))
+	violation := suite.assertViolation(err, PayoutPolicyViolationVelocity)
+	assert.Equal(suite.T(), 3.0, violation.Attempted)
+
+	//first payout leaves the window
+	suite.now = suite.now.Add(41 * time.Minute)
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r3", 100))
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestBlocklists() {
+	suite.policy.BlockAccount("+256777111786")
+	err := suite.testable.Check(buildStubScheduledPayoutRequest("r1", 100))
+	suite.assertViolation(err, PayoutPolicyViolationBlocked)
+	assert.Equal(suite.T(), `payout policy violation BLOCKED: account "256777111786" is blocked`, err.Error())
+
+	suite.policy.BlockProvider("airtel_ug")
+	req := buildStubScheduledPayoutRequest("r2", 100)
+	req.AccountNumber = "256752000123"
+	req.ProviderId = "airtel_ug"
+	suite.assertViolation(suite.testable.Check(req), PayoutPolicyViolationBlocked)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestRejectedPayoutIsReleased() {
+	suite.payouts.err = errors.New("Invalid account")
+	suite.payouts.response = &PayoutResponse{ResponseBody: ResponseBody{Code: http.StatusBadRequest, Message: "Invalid account"}}
+	_, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000))
+	assert.Equal(suite.T(), "Invalid account", err.Error())
+	suite.payouts.err = nil
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000))
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestAmbiguousFailureIsCounted() {
+	suite.payouts.err = errors.New("PayoutsResource.Create error: context deadline exceeded")
+	_, _, err := suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r1", 500000))
+	assert.Error(suite.T(), err)
+	suite.payouts.err = nil
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r2", 500000))
+	violation := suite.assertViolation(err, PayoutPolicyViolationBeneficiaryLimit)
+	assert.Equal(suite.T(), 1000000.0, violation.Attempted)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestInvalidRequestIsNotCounted() {
+	req := buildStubScheduledPayoutRequest("r1", 500000)
+	req.Narration = ""
+	_, _, err := suite.testable.Create(suite.ctx, req)
+	assert.Equal(suite.T(), `PayoutPolicyEnforcer.Create error: parameter "narration" is empty`, err.Error())
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+	_, _, err = suite.testable.Create(suite.ctx, buildStubScheduledPayoutRequest("r2", 500000))
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestAccountFormatsAreNormalized() {
+	suite.policy.BlockAccount("256772000001")
+	for _, account := range []string{"0772000001", "+256 772 000001", "256-772-000001"} {
+		req := buildStubScheduledPayoutRequest("r1", 100)
+		req.AccountNumber = account
+		suite.assertViolation(suite.testable.Check(req), PayoutPolicyViolationBlocked)
+	}
+
+	suite.policy.SetVelocity(1, time.Hour)
+	req := buildStubScheduledPayoutRequest("r2", 100)
+	req.AccountNumber = "0777111786"
+	_, _, err := suite.testable.Create(suite.ctx, req)
+	assert.NoError(suite.T(), err)
+	req = buildStubScheduledPayoutRequest("r3", 100)
+	req.AccountNumber = "+256 777 111786"
+	suite.assertViolation(suite.testable.Check(req), PayoutPolicyViolationVelocity)
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestBlockMobileAccount() {
+	assert.NoError(suite.T(), suite.policy.BlockMobileAccount("0777111786", CountryCodeUganda))
+	suite.assertViolation(suite.testable.Check(buildStubScheduledPayoutRequest("r1", 100)), PayoutPolicyViolationBlocked)
+	err := suite.policy.BlockMobileAccount("foo", CountryCodeUganda)
+	assert.Equal(suite.T(), `PayoutPolicy.BlockMobileAccount error: ParseMSISDN: number "foo" contains wrong characters`, err.Error())
+}
+
+func (suite *PayoutPolicyEnforcerTestSuite) TestNormalizePolicyAccount() {
+	assert.Equal(suite.T(), "0123456789", normalizePolicyAccount(" 0123-456 789 "))
+	assert.Equal(suite.T(), "0xabcdef", normalizePolicyAccount("0xAbCdEf"))
+}
+
+func TestPayoutPolicyEnforcerTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutPolicyEnforcerTestSuite))
+}
+
+type PayoutCounterMemoryStoreTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PayoutCounterMemoryStoreTestSuite) TestSumAndRetention() {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	store := NewPayoutCounterMemoryStore(MinPayoutCounterRetention)
+	_ = store.Add("key", 10.5, now)
+	_ = store.Add("key", 20, now.Add(30*time.Minute))
+	total, count, err := store.Sum("key", now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 30.5, total)
+	assert.Equal(suite.T(), 2, count)
+
+	at := now.Add(24*time.Hour + 30*time.Minute)
+	_ = store.Add("key", 5, at)
+	total, count, _ = store.Sum("key", now)
+	assert.Equal(suite.T(), 25.0, total)
+	assert.Equal(suite.T(), 2, count)
+
+	_ = store.Release("key", 5, at)
+	total, count, _ = store.Sum("key", now)
+	assert.Equal(suite.T(), 20.0, total)
+	assert.Equal(suite.T(), 1, count)
+}
+
+func (suite *PayoutCounterMemoryStoreTestSuite) TestShortRetentionIsClamped() {
+	now := time.Date(2022, 6, 1, 1, 0, 0, 0, time.UTC)
+	store := NewPayoutCounterMemoryStore(time.Hour)
+	_ = store.Add("key", 10, now)
+	_ = store.Add("key", 20, now.Add(20*time.Hour))
+	total, count, _ := store.Sum("key", now)
+	assert.Equal(suite.T(), 30.0, total)
+	assert.Equal(suite.T(), 2, count)
+
+	store.EnsureRetention(48 * time.Hour)
+	_ = store.Add("key", 5, now.Add(30*time.Hour))
+	total, count, _ = store.Sum("key", now)
+	assert.Equal(suite.T(), 35.0, total)
+	assert.Equal(suite.T(), 3, count)
+}
+
+func TestPayoutCounterMemoryStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutCounterMemoryStoreTestSuite))
+}
@@ -22,29 +22,50 @@ type PayoutRequest struct {
 		BankCode       string `json:"bank_code"`
 		BankBranchCode string `json:"branch_code"`
 	} `json:"extra_params"`
+	Crypto *CryptoParams `json:"crypto,omitempty"`
 }
 
 //Check is valid PayoutRequest parameters
 func (pr *PayoutRequest) isValid() error {
-	var err error
-	if pr.Currency == "" {
-		err = fmt.Errorf(`parameter "currency" is empty`)
-	} else if pr.Amount == 0 {
-		err = fmt.Errorf(`parameter "amount" is empty`)
-	} else if pr.Method == "" {
-		err = fmt.Errorf(`parameter "method" is empty`)
-	} else if pr.ProviderId == "" {
-		err = fmt.Errorf(`parameter "provider_id" is empty`)
-	} else if pr.MerchantReference == "" {
-		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
-	} else if pr.Narration == "" {
-		err = fmt.Errorf(`parameter "narration" is empty`)
-	} else if pr.AccountNumber == "" {
-		err = fmt.Errorf(`parameter "account_number" is empty`)
-	} else if pr.AccountName == "" {
-		err = fmt.Errorf(`parameter "account_name" is empty`)
+	return pr.Validate().first()
+}
+
+//Validate collect all PayoutRequest parameters errors (nil when the request is valid)
+func (pr *PayoutRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+	errs.addIf(pr.Currency == "", "currency", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.Currency != "" && !validationCurrencyRegexp.MatchString(string(pr.Currency)), "currency", ValidationReasonBadFormat, `value "%s" is not ISO 4217 currency code`, pr.Currency)
+	errs.addIf(pr.Amount == 0, "amount", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.Amount < 0, "amount", ValidationReasonOutOfRange, "is negative")
+	errs.addIf(pr.Method == "", "method", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.Method != "" && !isValidTransactionMethod(pr.Method), "method", ValidationReasonInvalidEnum, `value "%s" is not supported`, pr.Method)
+	errs.addIf(pr.ProviderId == "", "provider_id", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.MerchantReference == "", "merchant_reference", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.Narration == "", "narration", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.AccountNumber == "", "account_number", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.AccountName == "" && pr.Method != TransactionMethodCrypto, "account_name", ValidationReasonMissing, "is empty")
+	errs.addIf(pr.AccountEmail != "" && !validationEmailRegexp.MatchString(pr.AccountEmail), "account_email", ValidationReasonBadFormat, "is not valid email")
+	if pr.Method == TransactionMethodCrypto {
+		cryptoErrs := pr.Crypto.Validate()
+		errs = append(errs, cryptoErrs...)
+		if len(cryptoErrs) == 0 && pr.AccountNumber != "" {
+			errs.addIf(!IsValidCryptoWalletAddress(pr.Crypto.Network, pr.AccountNumber), "account_number", ValidationReasonBadFormat, `is not valid "%s" wallet address`, pr.Crypto.Network)
+		}
+	}
+	return errs
+}
+
+//copyPayoutRequest deep copy payout request
+func copyPayoutRequest(req *PayoutRequest) *PayoutRequest {
+	if req == nil {
+		return nil
+	}
+	c := *req
+	if req.Crypto != nil {
+		crypto := *req.Crypto
+		c.Crypto = &crypto
 	}
-	return err
+	return &c
 }
 
 //PayoutResponse struct
@@ -55,19 +76,20 @@ type PayoutResponse struct {
 
 //PayoutResponseData struct
 type PayoutResponseData struct {
-	ID                int64   `json:"id"`
-	RequestAmount     float64 `json:"request_amount"`
-	RequestCurrency   string  `json:"request_currency"`
-	AccountAmount     float64 `json:"account_amount"`
-	AccountCurrency   string  `json:"account_currency"`
-	TransactionFee    float64 `json:"transaction_fee"`
-	TotalDebit        float64 `json:"total_debit"`
-	ProviderID        string  `json:"provider_id"`
-	MerchantReference string  `json:"merchant_reference"`
-	InternalReference string  `json:"internal_reference"`
-	TransactionStatus string  `json:"transaction_status"`
-	TransactionType   string  `json:"transaction_type"`
-	Message           string  `json:"message"`
+	ID                int64                  `json:"id"`
+	RequestAmount     float64                `json:"request_amount"`
+	RequestCurrency   string                 `json:"request_currency"`
+	AccountAmount     float64                `json:"account_amount"`
+	AccountCurrency   string                 `json:"account_currency"`
+	TransactionFee    float64                `json:"transaction_fee"`
+	TotalDebit        float64                `json:"total_debit"`
+	ProviderID        string                 `json:"provider_id"`
+	MerchantReference string                 `json:"merchant_reference"`
+	InternalReference string                 `json:"internal_reference"`
+	TransactionStatus string                 `json:"transaction_status"`
+	TransactionType   string                 `json:"transaction_type"`
+	Message           string                 `json:"message"`
+	Crypto            *CryptoTransactionData `json:"crypto,omitempty"`
 }
 
 //PayoutsResource wrapper
@@ -0,0 +1,130 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//CaptureRequest capture of the authorised card collection (zero amount captures the whole authorised amount)
+type CaptureRequest struct {
+	InternalReference string  `json:"internal_reference"`
+	Amount            float64 `json:"amount,omitempty"`
+}
+
+//Check is valid CaptureRequest parameters
+func (cr *CaptureRequest) isValid() error {
+	var err error
+	if cr.InternalReference == "" {
+		err = fmt.Errorf(`parameter "internal_reference" is empty`)
+	} else if cr.Amount < 0 {
+		err = fmt.Errorf(`parameter "amount" is negative`)
+	}
+	return err
+}
+
+//ValidateAuthorization check is capture amount fits the authorised collection
+func (cr *CaptureRequest) ValidateAuthorization(authorization *CollectionResponseData) error {
+	if authorization.InternalReference != cr.InternalReference {
+		return fmt.Errorf(`authorization "%s" does not match capture "%s"`, authorization.InternalReference, cr.InternalReference)
+	}
+	if cr.Amount > authorization.RequestAmount {
+		return fmt.Errorf(`capture amount %v exceeds authorised amount %v`, cr.Amount, authorization.RequestAmount)
+	}
+	return nil
+}
+
+//VoidRequest release of the authorised card collection funds
+type VoidRequest struct {
+	InternalReference string `json:"internal_reference"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+//Check is valid VoidRequest parameters
+func (vr *VoidRequest) isValid() error {
+	var err error
+	if vr.InternalReference == "" {
+		err = fmt.Errorf(`parameter "internal_reference" is empty`)
+	}
+	return err
+}
+
+//Authorize create authorisation-only card collection request (see https://docs.dusupay.com/receiving-money/collections/pre-authorization)
+func (r *CollectionsResource) Authorize(ctx context.Context, req *CollectionRequest) (*CollectionResponse, *http.Response, error) {
+	if req.Method != TransactionMethodCard {
+		return nil, nil, fmt.Errorf(`CollectionsResource.Authorize error: parameter "method" must be "%s"`, TransactionMethodCard)
+	}
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Authorize error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Authorize error: %v", err)
+	}
+	post["authorize_only"] = true
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/collections", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Authorize error: %v", err)
+	}
+	var result CollectionResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("CollectionsResource.Authorize error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//Capture capture all or part of the authorised card collection (see https://docs.dusupay.com/receiving-money/collections/pre-authorization)
+func (r *CollectionsResource) Capture(ctx context.Context, req *CaptureRequest) (*CollectionResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Capture error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Capture error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/collections/capture", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Capture error: %v", err)
+	}
+	var result CollectionResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("CollectionsResource.Capture error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//Void release the authorised card collection funds (see https://docs.dusupay.com/receiving-money/collections/pre-authorization)
+func (r *CollectionsResource) Void(ctx context.Context, req *VoidRequest) (*CollectionResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Void error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Void error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/collections/void", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CollectionsResource.Void error: %v", err)
+	}
+	var result CollectionResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("CollectionsResource.Void error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
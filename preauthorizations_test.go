@@ -0,0 +1,221 @@
+package dusupay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type PreAuthorizationsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PreAuthorizationsTestSuite) TestCaptureRequestIsValidSuccess() {
+	request := CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001", Amount: 30}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *PreAuthorizationsTestSuite) TestCaptureRequestIsValidFullCapture() {
+	request := CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001"}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *PreAuthorizationsTestSuite) TestCaptureRequestIsValidEmptyInternalReference() {
+	request := CaptureRequest{Amount: 30}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "internal_reference" is empty`, result.Error())
+}
+
+func (suite *PreAuthorizationsTestSuite) TestCaptureRequestIsValidNegativeAmount() {
+	request := CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001", Amount: -1}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "amount" is negative`, result.Error())
+}
+
+func (suite *PreAuthorizationsTestSuite) TestCaptureRequestValidateAuthorization() {
+	authorization := &CollectionResponseData{InternalReference: "DUSUPAY405GZMPREAUTH001", RequestAmount: 50}
+	request := CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001", Amount: 30}
+	assert.NoError(suite.T(), request.ValidateAuthorization(authorization))
+	request.Amount = 60
+	result := request.ValidateAuthorization(authorization)
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `capture amount 60 exceeds authorised amount 50`, result.Error())
+	request.InternalReference = "other"
+	result = request.ValidateAuthorization(authorization)
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `authorization "DUSUPAY405GZMPREAUTH001" does not match capture "other"`, result.Error())
+}
+
+func (suite *PreAuthorizationsTestSuite) TestVoidRequestIsValidEmptyInternalReference() {
+	request := VoidRequest{Reason: "order cancelled"}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "internal_reference" is empty`, result.Error())
+}
+
+func TestPreAuthorizationsTestSuite(t *testing.T) {
+	suite.Run(t, new(PreAuthorizationsTestSuite))
+}
+
+type PreAuthorizationsResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *CollectionsResource
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &CollectionsResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestAuthorizeSuccess() {
+	body, _ := LoadStubResponseData("stubs/collections/authorize/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/collections", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &CollectionRequest{
+		Currency:          CurrencyCodeUSD,
+		Amount:            50,
+		Method:            TransactionMethodCard,
+		ProviderId:        "international_usd",
+		MerchantReference: "order-1001",
+		RedirectUrl:       "redirect_url",
+		Narration:         "narration",
+	}
+	result, resp, err := suite.testable.Authorize(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "DUSUPAY405GZMPREAUTH001", result.Data.InternalReference)
+	assert.Equal(suite.T(), 50.0, result.Data.RequestAmount)
+	assert.Equal(suite.T(), "PENDING", result.Data.TransactionStatus)
+	assert.Equal(suite.T(), false, result.Data.CustomerCharged)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestAuthorizeWrongMethod() {
+	request := &CollectionRequest{
+		Currency:          CurrencyCodeKES,
+		Amount:            100,
+		Method:            TransactionMethodBank,
+		ProviderId:        "provider_id",
+		MerchantReference: "merchant_reference",
+		RedirectUrl:       "redirect_url",
+		Narration:         "narration",
+	}
+	result, rsp, err := suite.testable.Authorize(suite.ctx, request)
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `CollectionsResource.Authorize error: parameter "method" must be "CARD"`, err.Error())
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestAuthorizeInvalidRequest() {
+	req := &CollectionRequest{Method: TransactionMethodCard}
+	result, rsp, err := suite.testable.Authorize(suite.ctx, req)
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestCaptureSuccess() {
+	body, _ := LoadStubResponseData("stubs/collections/capture/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/collections/capture", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001", Amount: 30}
+	result, resp, err := suite.testable.Capture(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Capture Initiated", result.Message)
+	assert.Equal(suite.T(), 30.0, result.Data.RequestAmount)
+	assert.Equal(suite.T(), 28.95, result.Data.TotalCredit)
+	assert.Equal(suite.T(), "COMPLETED", result.Data.TransactionStatus)
+	assert.Equal(suite.T(), true, result.Data.CustomerCharged)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestCaptureJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/collections/capture", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001"}
+	result, resp, err := suite.testable.Capture(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestCaptureNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/collections/capture", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &CaptureRequest{InternalReference: "DUSUPAY405GZMPREAUTH001"}
+	result, resp, err := suite.testable.Capture(suite.ctx, request)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestCaptureInvalidRequest() {
+	result, rsp, err := suite.testable.Capture(suite.ctx, &CaptureRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestVoidSuccess() {
+	body, _ := LoadStubResponseData("stubs/collections/void/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/collections/void", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	request := &VoidRequest{InternalReference: "DUSUPAY405GZMPREAUTH001", Reason: "order cancelled"}
+	result, resp, err := suite.testable.Void(suite.ctx, request)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.NotEmpty(suite.T(), result)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Authorization Voided", result.Data.Message)
+	assert.Equal(suite.T(), "CANCELLED", result.Data.TransactionStatus)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *PreAuthorizationsResourceTestSuite) TestVoidInvalidRequest() {
+	result, rsp, err := suite.testable.Void(suite.ctx, &VoidRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func TestPreAuthorizationsResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(PreAuthorizationsResourceTestSuite))
+}
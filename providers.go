@@ -18,15 +18,19 @@ type ProvidersFilter struct {
 
 //Check is valid ProvidersFilter parameters
 func (pf *ProvidersFilter) isValid() error {
-	var err error
-	if pf.Country == "" {
-		err = fmt.Errorf(`parameter "country_code" is empty`)
-	} else if pf.Method == "" {
-		err = fmt.Errorf(`parameter "method" is empty`)
-	} else if pf.TransactionType == "" {
-		err = fmt.Errorf(`parameter "transaction_type" is empty`)
-	}
-	return err
+	return pf.Validate().first()
+}
+
+//Validate collect all ProvidersFilter parameters errors (nil when the filter is valid)
+func (pf *ProvidersFilter) Validate() ValidationErrors {
+	var errs ValidationErrors
+	errs.addIf(pf.Country == "", "country_code", ValidationReasonMissing, "is empty")
+	errs.addIf(pf.Country != "" && !validationCountryRegexp.MatchString(string(pf.Country)), "country_code", ValidationReasonBadFormat, `value "%s" is not ISO 3166-1 alpha-2 country code`, pf.Country)
+	errs.addIf(pf.Method == "", "method", ValidationReasonMissing, "is empty")
+	errs.addIf(pf.Method != "" && !isValidTransactionMethod(pf.Method), "method", ValidationReasonInvalidEnum, `value "%s" is not supported`, pf.Method)
+	errs.addIf(pf.TransactionType == "", "transaction_type", ValidationReasonMissing, "is empty")
+	errs.addIf(pf.TransactionType != "" && !isValidFilterTransactionType(pf.TransactionType), "transaction_type", ValidationReasonInvalidEnum, `value "%s" is not supported`, pf.TransactionType)
+	return errs
 }
 
 //buildPath method
@@ -0,0 +1,182 @@
+package dusupay
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+//CollectionRedirect query parameters appended by Dusupay to the collection "redirect_url"
+type CollectionRedirect struct {
+	ID                int64  `json:"id"`
+	MerchantReference string `json:"merchant_reference"`
+	InternalReference string `json:"internal_reference"`
+	TransactionStatus string `json:"transaction_status"`
+	Message           string `json:"message"`
+	Signature         string `json:"signature"`
+}
+
+//BuildPayloadString method
+func (cr *CollectionRedirect) BuildPayloadString(url string) string {
+	return fmt.Sprintf("%d:%s:%s:%s", cr.ID, cr.InternalReference, cr.TransactionStatus, url)
+}
+
+//ParseCollectionRedirect parse redirect url query parameters (see https://docs.dusupay.com/webhooks-and-redirects/redirects)
+func ParseCollectionRedirect(query url.Values) (*CollectionRedirect, error) {
+	redirect := &CollectionRedirect{
+		MerchantReference: query.Get("merchant_reference"),
+		InternalReference: query.Get("internal_reference"),
+		TransactionStatus: query.Get("transaction_status"),
+		Message:           query.Get("message"),
+		Signature:         query.Get("signature"),
+	}
+	var err error
+	if query.Get("id") == "" {
+		err = fmt.Errorf(`parameter "id" is empty`)
+	} else if redirect.ID, err = strconv.ParseInt(query.Get("id"), 10, 64); err != nil {
+		err = fmt.Errorf(`parameter "id" is wrong: %v`, err)
+	} else if redirect.InternalReference == "" {
+		err = fmt.Errorf(`parameter "internal_reference" is empty`)
+	} else if redirect.MerchantReference == "" {
+		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
+	} else if redirect.TransactionStatus == "" {
+		err = fmt.Errorf(`parameter "transaction_status" is empty`)
+	} else if redirect.Signature == "" {
+		err = fmt.Errorf(`parameter "signature" is empty`)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return redirect, nil
+}
+
+//CollectionRedirectResult verified customer redirect
+type CollectionRedirectResult struct {
+	Redirect *CollectionRedirect
+	Status   TransactionStatusCode
+	//originating collection request (if resolver is defined), without resolver MerchantReference is not verified
+	Request *CollectionRequest
+}
+
+//IsCompleted check is collection completed
+func (crr *CollectionRedirectResult) IsCompleted() bool {
+	return crr.Status == TransactionStatusCompleted
+}
+
+//IsPending check is collection pending
+func (crr *CollectionRedirectResult) IsPending() bool {
+	return crr.Status == TransactionStatusPending
+}
+
+//IsFailed check is collection failed or cancelled
+func (crr *CollectionRedirectResult) IsFailed() bool {
+	return crr.Status == TransactionStatusFailed || crr.Status == TransactionStatusCancelled
+}
+
+//CollectionRequestResolverInterface lookup of the originating collection request by internal reference (covered by redirect signature)
+type CollectionRequestResolverInterface interface {
+	ResolveCollectionRequest(internalReference string) (*CollectionRequest, error)
+}
+
+//NewCollectionRequestRegistry create new in-memory collection requests registry
+func NewCollectionRequestRegistry() *CollectionRequestRegistry {
+	return &CollectionRequestRegistry{requests: make(map[string]*CollectionRequest)}
+}
+
+//CollectionRequestRegistry in-memory collection requests resolver
+type CollectionRequestRegistry struct {
+	mu       sync.RWMutex
+	requests map[string]*CollectionRequest
+}
+
+//Register store collection request by the internal reference returned by collection create
+func (crr *CollectionRequestRegistry) Register(req *CollectionRequest, data *CollectionResponseData) error {
+	if data == nil || data.InternalReference == "" {
+		return errors.New(`CollectionRequestRegistry.Register error: parameter "internal_reference" is empty`)
+	}
+	crr.mu.Lock()
+	defer crr.mu.Unlock()
+	crr.requests[data.InternalReference] = req
+	return nil
+}
+
+//ResolveCollectionRequest method
+func (crr *CollectionRequestRegistry) ResolveCollectionRequest(internalReference string) (*CollectionRequest, error) {
+	crr.mu.RLock()
+	defer crr.mu.RUnlock()
+	req, ok := crr.requests[internalReference]
+	if !ok {
+		return nil, fmt.Errorf(`collection request "%s" not found`, internalReference)
+	}
+	return req, nil
+}
+
+//NewRedirectHandler create new redirect handler (resolver is optional)
+func NewRedirectHandler(validator SignatureValidatorInterface, resolver CollectionRequestResolverInterface) *RedirectHandler {
+	return &RedirectHandler{validator: validator, resolver: resolver}
+}
+
+//RedirectHandler handler of the customer coming back from card payment page (3-D Secure)
+type RedirectHandler struct {
+	validator SignatureValidatorInterface
+	resolver  CollectionRequestResolverInterface
+}
+
+//Handle parse and verify redirect url
+func (rh *RedirectHandler) Handle(redirectUrl *url.URL) (*CollectionRedirectResult, error) {
+	redirect, err := ParseCollectionRedirect(redirectUrl.Query())
+	if err != nil {
+		return nil, fmt.Errorf("RedirectHandler.Handle error: %v", err)
+	}
+	result := &CollectionRedirectResult{Redirect: redirect, Status: TransactionStatusCode(redirect.TransactionStatus)}
+	//signature is built from the "redirect_url" sent with collection request
+	signedUrl := buildRedirectSignedUrl(redirectUrl)
+	if rh.resolver != nil {
+		//merchant reference is not signed, so it is bound through the signed internal reference
+		result.Request, err = rh.resolver.ResolveCollectionRequest(redirect.InternalReference)
+		if err != nil {
+			return nil, fmt.Errorf("RedirectHandler.Handle error: %v", err)
+		}
+		if result.Request.MerchantReference != redirect.MerchantReference {
+			return nil, errors.New("RedirectHandler.Handle error: merchant reference mismatch")
+		}
+		if result.Request.RedirectUrl != "" {
+			signedUrl = result.Request.RedirectUrl
+		}
+	}
+	err = rh.validator.ValidateSignature(redirect, signedUrl, redirect.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("RedirectHandler.Handle wrong signature: %v", err)
+	}
+	return result, nil
+}
+
+//HandleRequest parse and verify incoming http redirect request
+func (rh *RedirectHandler) HandleRequest(req *http.Request) (*CollectionRedirectResult, error) {
+	u := *req.URL
+	if u.Host == "" {
+		u.Host = req.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if req.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return rh.Handle(&u)
+}
+
+//buildRedirectSignedUrl redirect url without Dusupay query parameters
+func buildRedirectSignedUrl(redirectUrl *url.URL) string {
+	u := *redirectUrl
+	q := u.Query()
+	for _, param := range []string{"id", "merchant_reference", "internal_reference", "transaction_status", "message", "signature"} {
+		q.Del(param)
+	}
+	u.RawQuery = q.Encode()
+	u.Fragment = ""
+	return u.String()
+}
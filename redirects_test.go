@@ -0,0 +1,182 @@
+package dusupay
+
+import (
+	"crypto/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type RedirectsTestSuite struct {
+	suite.Suite
+	validator *SignatureValidator
+}
+
+func (suite *RedirectsTestSuite) SetupTest() {
+	rawBytes, _ := ioutil.ReadFile("stubs/rsa/public-key.pem")
+	suite.validator, _ = NewSignatureValidator(rawBytes)
+}
+
+func (suite *RedirectsTestSuite) buildRedirectUrl(base string, status string) *url.URL {
+	u, _ := url.Parse(base)
+	q := u.Query()
+	q.Set("id", "226")
+	q.Set("merchant_reference", "76859aae-f148-48c5-9901-2e474cf19b71")
+	q.Set("internal_reference", "DUSUPAY405GZM1G5JXGA71IK")
+	q.Set("transaction_status", status)
+	q.Set("signature", stubSignature)
+	u.RawQuery = q.Encode()
+	return u
+}
+
+func (suite *RedirectsTestSuite) TestParseCollectionRedirectSuccess() {
+	u := suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED")
+	result, err := ParseCollectionRedirect(u.Query())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(226), result.ID)
+	assert.Equal(suite.T(), "76859aae-f148-48c5-9901-2e474cf19b71", result.MerchantReference)
+	assert.Equal(suite.T(), "DUSUPAY405GZM1G5JXGA71IK", result.InternalReference)
+	assert.Equal(suite.T(), "COMPLETED", result.TransactionStatus)
+	assert.Equal(suite.T(), stubSignature, result.Signature)
+	assert.Equal(suite.T(), "226:DUSUPAY405GZM1G5JXGA71IK:COMPLETED:https://www.sample-url.com/callback", result.BuildPayloadString("https://www.sample-url.com/callback"))
+}
+
+func (suite *RedirectsTestSuite) TestParseCollectionRedirectErrors() {
+	cases := map[string]string{
+		"id":                 `parameter "id" is empty`,
+		"internal_reference": `parameter "internal_reference" is empty`,
+		"merchant_reference": `parameter "merchant_reference" is empty`,
+		"transaction_status": `parameter "transaction_status" is empty`,
+		"signature":          `parameter "signature" is empty`,
+	}
+	for param, message := range cases {
+		q := suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED").Query()
+		q.Del(param)
+		result, err := ParseCollectionRedirect(q)
+		assert.Nil(suite.T(), result)
+		assert.Error(suite.T(), err)
+		assert.Equal(suite.T(), message, err.Error())
+	}
+	q := suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED").Query()
+	q.Set("id", "foo")
+	_, err := ParseCollectionRedirect(q)
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), `parameter "id" is wrong`)
+}
+
+func (suite *RedirectsTestSuite) TestHandleSuccess() {
+	handler := NewRedirectHandler(suite.validator, nil)
+	result, err := handler.Handle(suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionStatusCompleted, result.Status)
+	assert.True(suite.T(), result.IsCompleted())
+	assert.False(suite.T(), result.IsPending())
+	assert.False(suite.T(), result.IsFailed())
+	assert.Nil(suite.T(), result.Request)
+}
+
+func (suite *RedirectsTestSuite) TestHandleWithMultiKeyValidator() {
+	keyBytes, _ := ioutil.ReadFile("stubs/rsa/public-key.pem")
+	validator := NewMultiKeySignatureValidator()
+	_ = validator.AddKey("2020", keyBytes, time.Time{})
+	handler := NewRedirectHandler(validator, nil)
+	result, err := handler.Handle(suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED"))
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.IsCompleted())
+}
+
+func (suite *RedirectsTestSuite) TestHandleWrongSignature() {
+	handler := NewRedirectHandler(suite.validator, nil)
+	result, err := handler.Handle(suite.buildRedirectUrl("https://www.sample-url.com/callback", "FAILED"))
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "RedirectHandler.Handle wrong signature: crypto/rsa: verification error", err.Error())
+}
+
+func (suite *RedirectsTestSuite) TestHandleWrongParameters() {
+	handler := NewRedirectHandler(suite.validator, nil)
+	u, _ := url.Parse("https://www.sample-url.com/callback")
+	result, err := handler.Handle(u)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RedirectHandler.Handle error: parameter "id" is empty`, err.Error())
+}
+
+func (suite *RedirectsTestSuite) TestHandleWithResolverSuccess() {
+	registry := NewCollectionRequestRegistry()
+	request := &CollectionRequest{
+		Method:            TransactionMethodCard,
+		MerchantReference: "76859aae-f148-48c5-9901-2e474cf19b71",
+		RedirectUrl:       "https://www.sample-url.com/callback",
+	}
+	_ = registry.Register(request, &CollectionResponseData{InternalReference: "DUSUPAY405GZM1G5JXGA71IK"})
+	handler := NewRedirectHandler(suite.validator, registry)
+	//customer came back through the url with additional tracking parameters
+	result, err := handler.Handle(suite.buildRedirectUrl("https://www.sample-url.com/callback?utm_source=foo", "COMPLETED"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), request, result.Request)
+}
+
+func (suite *RedirectsTestSuite) TestHandleWithResolverMerchantReferenceSwapped() {
+	registry := NewCollectionRequestRegistry()
+	_ = registry.Register(&CollectionRequest{
+		Method:            TransactionMethodCard,
+		MerchantReference: "76859aae-f148-48c5-9901-2e474cf19b71",
+		RedirectUrl:       "https://www.sample-url.com/callback",
+	}, &CollectionResponseData{InternalReference: "DUSUPAY405GZM1G5JXGA71IK"})
+	_ = registry.Register(&CollectionRequest{
+		Method:            TransactionMethodCard,
+		MerchantReference: "another-order",
+		RedirectUrl:       "https://www.sample-url.com/callback",
+	}, &CollectionResponseData{InternalReference: "DUSUPAYANOTHER"})
+	handler := NewRedirectHandler(suite.validator, registry)
+	//valid signed redirect replayed with another order merchant reference
+	u := suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED")
+	q := u.Query()
+	q.Set("merchant_reference", "another-order")
+	u.RawQuery = q.Encode()
+	result, err := handler.Handle(u)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "RedirectHandler.Handle error: merchant reference mismatch", err.Error())
+}
+
+func (suite *RedirectsTestSuite) TestRegisterWithoutInternalReference() {
+	registry := NewCollectionRequestRegistry()
+	err := registry.Register(&CollectionRequest{MerchantReference: "foo"}, &CollectionResponseData{})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `CollectionRequestRegistry.Register error: parameter "internal_reference" is empty`, err.Error())
+}
+
+func (suite *RedirectsTestSuite) TestHandleWithResolverNotFound() {
+	handler := NewRedirectHandler(suite.validator, NewCollectionRequestRegistry())
+	result, err := handler.Handle(suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED"))
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RedirectHandler.Handle error: collection request "DUSUPAY405GZM1G5JXGA71IK" not found`, err.Error())
+}
+
+func (suite *RedirectsTestSuite) TestHandleRequestSuccess() {
+	u := suite.buildRedirectUrl("https://www.sample-url.com/callback", "COMPLETED")
+	req, _ := http.NewRequest(http.MethodGet, u.RequestURI(), nil)
+	req.Host = "www.sample-url.com"
+	req.TLS = &tls.ConnectionState{}
+	handler := NewRedirectHandler(suite.validator, nil)
+	result, err := handler.HandleRequest(req)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.IsCompleted())
+}
+
+func (suite *RedirectsTestSuite) TestCollectionRedirectResultStatuses() {
+	assert.True(suite.T(), (&CollectionRedirectResult{Status: TransactionStatusPending}).IsPending())
+	assert.True(suite.T(), (&CollectionRedirectResult{Status: TransactionStatusFailed}).IsFailed())
+	assert.True(suite.T(), (&CollectionRedirectResult{Status: TransactionStatusCancelled}).IsFailed())
+}
+
+func TestRedirectsTestSuite(t *testing.T) {
+	suite.Run(t, new(RedirectsTestSuite))
+}
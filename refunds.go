@@ -2,9 +2,11 @@ package dusupay
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 //RefundRequest struct
@@ -13,13 +15,25 @@ type RefundRequest struct {
 	InternalReference string  `json:"internal_reference"`
 }
 
-//Check is valid PayoutRequest parameters
+//Check is valid RefundRequest parameters
 func (rr *RefundRequest) isValid() error {
-	var err error
-	if rr.InternalReference == "" {
-		err = fmt.Errorf(`parameter "internal_reference" is empty`)
-	}
-	return err
+	return rr.Validate().first()
+}
+
+//Validate collect all RefundRequest parameters errors (nil when the request is valid)
+func (rr *RefundRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+	errs.addIf(rr.InternalReference == "", "internal_reference", ValidationReasonMissing, "is empty")
+	errs.addIf(rr.Amount == 0, "amount", ValidationReasonMissing, "is empty")
+	errs.addIf(rr.Amount < 0, "amount", ValidationReasonOutOfRange, "is negative")
+	return errs
+}
+
+//RefundsFilter refunds list filter (all parameters are optional)
+type RefundsFilter struct {
+	CollectionReference string                `json:"collection_reference,omitempty"`
+	Status              TransactionStatusCode `json:"transaction_status,omitempty"`
+	Page                int                   `json:"page,omitempty"`
 }
 
 //RefundResponse struct
@@ -45,6 +59,34 @@ type RefundResponseData struct {
 	Message             string  `json:"message"`
 }
 
+//GetStatus typed refund status
+func (rrd *RefundResponseData) GetStatus() TransactionStatusCode {
+	return TransactionStatusCode(strings.ToUpper(rrd.TransactionStatus))
+}
+
+//RefundsResponse struct
+type RefundsResponse struct {
+	ResponseBody
+	Data *RefundsResponseData `json:"data,omitempty"`
+}
+
+//RefundsResponseData struct
+type RefundsResponseData []*RefundResponseData
+
+//UnmarshalJSON unmarshal json data
+func (rsp *RefundsResponseData) UnmarshalJSON(data []byte) error {
+	if isEmptyObjectResponseData(data) {
+		return nil
+	}
+	var arr []*RefundResponseData
+	err := json.Unmarshal(data, &arr)
+	if err != nil {
+		return err
+	}
+	*rsp = append(*rsp, arr...)
+	return nil
+}
+
 //RefundsResource wrapper
 type RefundsResource struct {
 	ResourceAbstract
@@ -74,3 +116,47 @@ func (r *RefundsResource) Create(ctx context.Context, req *RefundRequest) (*Refu
 	}
 	return &result, rsp, err
 }
+
+//GetList get refunds list (see https://docs.dusupay.com/appendix/refunds)
+func (r *RefundsResource) GetList(ctx context.Context, filter *RefundsFilter) (*RefundsResponse, *http.Response, error) {
+	if filter == nil {
+		filter = &RefundsFilter{}
+	}
+	query, err := transformStructToMap(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("RefundsResource.GetList error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/refunds", query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("RefundsResource.GetList error: %v", err)
+	}
+	var result RefundsResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("RefundsResource.GetList error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//Get refund by its internal reference (see https://docs.dusupay.com/appendix/refunds)
+func (r *RefundsResource) Get(ctx context.Context, internalReference string) (*RefundResponse, *http.Response, error) {
+	if internalReference == "" {
+		return nil, nil, fmt.Errorf(`RefundsResource.Get error: parameter "internal_reference" is empty`)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/refund/"+internalReference, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("RefundsResource.Get error: %v", err)
+	}
+	var result RefundResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("RefundsResource.Get error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
@@ -32,6 +32,30 @@ func (suite *RefundsTestSuite) TestRefundsRequestIsValidEmptyInternalReference()
 	assert.Equal(suite.T(), `parameter "internal_reference" is empty`, result.Error())
 }
 
+func (suite *RefundsTestSuite) TestRefundsRequestIsValidEmptyAmount() {
+	request := RefundRequest{
+		InternalReference: "internal_reference",
+	}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "amount" is empty`, result.Error())
+}
+
+func (suite *RefundsTestSuite) TestRefundsRequestIsValidNegativeAmount() {
+	request := RefundRequest{
+		Amount:            -100,
+		InternalReference: "internal_reference",
+	}
+	result := request.isValid()
+	assert.Error(suite.T(), result)
+	assert.Equal(suite.T(), `parameter "amount" is negative`, result.Error())
+}
+
+func (suite *RefundsTestSuite) TestRefundResponseDataGetStatus() {
+	data := &RefundResponseData{TransactionStatus: "pending"}
+	assert.Equal(suite.T(), TransactionStatusPending, data.GetStatus())
+}
+
 func TestRefundsTestSuite(t *testing.T) {
 	suite.Run(t, new(RefundsTestSuite))
 }
@@ -144,6 +168,94 @@ func (suite *RefundsResourceTestSuite) TestCreateInvalidRequest() {
 	assert.Error(suite.T(), err)
 }
 
+func (suite *RefundsResourceTestSuite) TestGetListSuccess() {
+	body, _ := LoadStubResponseData("stubs/refunds/list/success.json")
+	var query map[string][]string
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/refunds", func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query()
+		return httpmock.NewBytesResponse(http.StatusOK, body), nil
+	})
+
+	filter := &RefundsFilter{CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX"}
+	result, resp, err := suite.testable.GetList(suite.ctx, filter)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Equal(suite.T(), []string{"DUSUPAYXYXYXYXYXYXYXYXYX"}, query["collection_reference"])
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Len(suite.T(), *result.Data, 2)
+	assert.Equal(suite.T(), int64(65205), (*result.Data)[0].ID)
+	assert.Equal(suite.T(), TransactionStatusCompleted, (*result.Data)[0].GetStatus())
+	assert.Equal(suite.T(), int64(65206), (*result.Data)[1].ID)
+	assert.Equal(suite.T(), float64(500), (*result.Data)[1].RefundAmount)
+	assert.Equal(suite.T(), TransactionStatusPending, (*result.Data)[1].GetStatus())
+}
+
+func (suite *RefundsResourceTestSuite) TestGetListJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/refunds", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetList(suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *RefundsResourceTestSuite) TestGetListNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/refunds", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetList(suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *RefundsResourceTestSuite) TestGetSuccess() {
+	body, _ := LoadStubResponseData("stubs/refunds/get/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/refund/RFD-DUSUPAYXYXYXYXYXYXYXYXYX-3486003", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Get(suite.ctx, "RFD-DUSUPAYXYXYXYXYXYXYXYXYX-3486003")
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), int64(65205), result.Data.ID)
+	assert.Equal(suite.T(), "DUSUPAYXYXYXYXYXYXYXYXYX", result.Data.CollectionReference)
+	assert.Equal(suite.T(), TransactionStatusCompleted, result.Data.GetStatus())
+}
+
+func (suite *RefundsResourceTestSuite) TestGetJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/refund/foo", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Get(suite.ctx, "foo")
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *RefundsResourceTestSuite) TestGetNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/refund/foo", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Get(suite.ctx, "foo")
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *RefundsResourceTestSuite) TestGetEmptyReference() {
+	result, rsp, err := suite.testable.Get(suite.ctx, "")
+	assert.Nil(suite.T(), result)
+	assert.Nil(suite.T(), rsp)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RefundsResource.Get error: parameter "internal_reference" is empty`, err.Error())
+}
+
 func TestRefundsResourceTestSuite(t *testing.T) {
 	suite.Run(t, new(RefundsResourceTestSuite))
 }
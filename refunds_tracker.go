@@ -0,0 +1,215 @@
+package dusupay
+
+import (
+	"fmt"
+	"sync"
+)
+
+//RefundBalance collection refunds accounting
+type RefundBalance struct {
+	CollectionReference string  `json:"collection_reference"`
+	Currency            string  `json:"currency"`
+	TotalCredit         float64 `json:"total_credit"`
+	//completed refunds amount
+	Refunded float64 `json:"refunded"`
+	//initiated but not completed refunds amount
+	Pending float64 `json:"pending"`
+}
+
+//Available amount which still can be refunded
+func (rb *RefundBalance) Available() float64 {
+	return roundAmount(rb.TotalCredit - rb.Refunded - rb.Pending)
+}
+
+//trackedRefund refund state
+type trackedRefund struct {
+	collectionReference string
+	amount              float64
+	status              TransactionStatusCode
+}
+
+//RefundReservation refund amount booked as pending before the refund request is sent
+type RefundReservation struct {
+	ID                  string  `json:"id"`
+	CollectionReference string  `json:"collection_reference"`
+	Amount              float64 `json:"amount"`
+}
+
+//NewRefundTracker create new refund tracker
+func NewRefundTracker() *RefundTracker {
+	return &RefundTracker{
+		balances:     make(map[string]*RefundBalance),
+		refunds:      make(map[string]*trackedRefund),
+		reservations: make(map[string]*RefundReservation),
+	}
+}
+
+//RefundTracker cumulative refunded amount per collection tracker
+type RefundTracker struct {
+	mu           sync.Mutex
+	balances     map[string]*RefundBalance
+	refunds      map[string]*trackedRefund
+	reservations map[string]*RefundReservation
+	sequence     int
+}
+
+//TrackCollection register collection available for refunds
+func (rt *RefundTracker) TrackCollection(collectionReference string, currency string, totalCredit float64) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if balance, ok := rt.balances[collectionReference]; ok {
+		balance.Currency = currency
+		balance.TotalCredit = totalCredit
+		return
+	}
+	rt.balances[collectionReference] = &RefundBalance{CollectionReference: collectionReference, Currency: currency, TotalCredit: totalCredit}
+}
+
+//TrackCollectionWebhook register completed collection available for refunds
+func (rt *RefundTracker) TrackCollectionWebhook(webhook *CollectionWebhook) {
+	if TransactionStatusCode(webhook.TransactionStatus) != TransactionStatusCompleted {
+		return
+	}
+	rt.TrackCollection(webhook.InternalReference, webhook.AccountCurrency, webhook.TotalCredit)
+}
+
+//GetBalance get collection refunds accounting
+func (rt *RefundTracker) GetBalance(collectionReference string) (*RefundBalance, bool) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	balance, ok := rt.balances[collectionReference]
+	if !ok {
+		return nil, false
+	}
+	b := *balance
+	return &b, true
+}
+
+//CheckRefund check is refund request fits the original collection total credit (the amount is not booked, see ReserveRefund)
+func (rt *RefundTracker) CheckRefund(req *RefundRequest) error {
+	err := req.isValid()
+	if err != nil {
+		return fmt.Errorf("RefundTracker.CheckRefund error: %v", err)
+	}
+	balance, ok := rt.GetBalance(req.InternalReference)
+	if !ok {
+		return fmt.Errorf(`RefundTracker.CheckRefund error: collection "%s" is not tracked`, req.InternalReference)
+	}
+	if req.Amount > balance.Available() {
+		return fmt.Errorf(`RefundTracker.CheckRefund error: refund amount %v exceeds available amount %v`, req.Amount, balance.Available())
+	}
+	return nil
+}
+
+//ReserveRefund check refund request and book its amount as pending (concurrent refunds can not exceed the total credit)
+func (rt *RefundTracker) ReserveRefund(req *RefundRequest) (*RefundReservation, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, fmt.Errorf("RefundTracker.ReserveRefund error: %v", err)
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	balance, ok := rt.balances[req.InternalReference]
+	if !ok {
+		return nil, fmt.Errorf(`RefundTracker.ReserveRefund error: collection "%s" is not tracked`, req.InternalReference)
+	}
+	if req.Amount > balance.Available() {
+		return nil, fmt.Errorf(`RefundTracker.ReserveRefund error: refund amount %v exceeds available amount %v`, req.Amount, balance.Available())
+	}
+	rt.sequence++
+	reservation := &RefundReservation{ID: fmt.Sprintf("reservation-%d", rt.sequence), CollectionReference: req.InternalReference, Amount: req.Amount}
+	rt.reservations[reservation.ID] = reservation
+	balance.Pending = roundAmount(balance.Pending + reservation.Amount)
+	r := *reservation
+	return &r, nil
+}
+
+//ConfirmReservation replace reservation with the created refund (the refund status is tracked from now on)
+func (rt *RefundTracker) ConfirmReservation(id string, data *RefundResponseData) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	reservation, ok := rt.reservations[id]
+	if !ok {
+		return fmt.Errorf(`RefundTracker.ConfirmReservation error: reservation "%s" not found`, id)
+	}
+	if reservation.CollectionReference != data.CollectionReference {
+		return fmt.Errorf(`RefundTracker.ConfirmReservation error: refund collection "%s" differs from reserved collection "%s"`, data.CollectionReference, reservation.CollectionReference)
+	}
+	rt.releaseReservation(reservation)
+	err := rt.trackRefund(data.InternalReference, data.CollectionReference, data.RefundCurrency, data.RefundAmount, data.GetStatus())
+	if err != nil {
+		rt.reservations[id] = reservation
+		balance := rt.balances[reservation.CollectionReference]
+		balance.Pending = roundAmount(balance.Pending + reservation.Amount)
+		return fmt.Errorf("RefundTracker.ConfirmReservation error: %v", err)
+	}
+	return nil
+}
+
+//ReleaseReservation release reserved amount when the refund request is definitely rejected
+//(keep the reservation on ambiguous errors until the refund is found and confirmed)
+func (rt *RefundTracker) ReleaseReservation(id string) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	reservation, ok := rt.reservations[id]
+	if !ok {
+		return fmt.Errorf(`RefundTracker.ReleaseReservation error: reservation "%s" not found`, id)
+	}
+	rt.releaseReservation(reservation)
+	return nil
+}
+
+//releaseReservation remove reservation and its pending amount
+func (rt *RefundTracker) releaseReservation(reservation *RefundReservation) {
+	delete(rt.reservations, reservation.ID)
+	if balance, ok := rt.balances[reservation.CollectionReference]; ok {
+		balance.Pending = roundAmount(balance.Pending - reservation.Amount)
+	}
+}
+
+//TrackRefund update refunded amounts from refund response
+func (rt *RefundTracker) TrackRefund(data *RefundResponseData) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.trackRefund(data.InternalReference, data.CollectionReference, data.RefundCurrency, data.RefundAmount, data.GetStatus())
+}
+
+//TrackRefundWebhook update refunded amounts from refund webhook
+func (rt *RefundTracker) TrackRefundWebhook(webhook *RefundWebhook) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.trackRefund(webhook.InternalReference, webhook.CollectionReference, webhook.RefundCurrency, webhook.RefundAmount, webhook.GetStatus())
+}
+
+//trackRefund move refund amount between pending and refunded amounts by status (the caller holds the lock)
+func (rt *RefundTracker) trackRefund(internalReference string, collectionReference string, currency string, amount float64, status TransactionStatusCode) error {
+	balance, ok := rt.balances[collectionReference]
+	if !ok {
+		return fmt.Errorf(`RefundTracker.TrackRefund error: collection "%s" is not tracked`, collectionReference)
+	}
+	if balance.Currency != "" && currency != "" && balance.Currency != currency {
+		return fmt.Errorf(`RefundTracker.TrackRefund error: refund currency "%s" differs from collection currency "%s"`, currency, balance.Currency)
+	}
+	refund, ok := rt.refunds[internalReference]
+	if ok {
+		//rollback previous state
+		rt.applyRefund(balance, refund, -1)
+	} else {
+		refund = &trackedRefund{collectionReference: collectionReference}
+		rt.refunds[internalReference] = refund
+	}
+	refund.amount = amount
+	refund.status = status
+	rt.applyRefund(balance, refund, 1)
+	return nil
+}
+
+//applyRefund add (sign = 1) or remove (sign = -1) refund amount
+func (rt *RefundTracker) applyRefund(balance *RefundBalance, refund *trackedRefund, sign float64) {
+	switch refund.status {
+	case TransactionStatusCompleted:
+		balance.Refunded = roundAmount(balance.Refunded + sign*refund.amount)
+	case TransactionStatusPending:
+		balance.Pending = roundAmount(balance.Pending + sign*refund.amount)
+	}
+}
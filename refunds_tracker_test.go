@@ -0,0 +1,167 @@
+package dusupay
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RefundTrackerTestSuite struct {
+	suite.Suite
+	testable *RefundTracker
+}
+
+func (suite *RefundTrackerTestSuite) SetupTest() {
+	suite.testable = NewRefundTracker()
+	suite.testable.TrackCollection("DUSUPAYXYXYXYXYXYXYXYXYX", "UGX", 2000)
+}
+
+func (suite *RefundTrackerTestSuite) TestCheckRefundSuccess() {
+	assert.NoError(suite.T(), suite.testable.CheckRefund(&RefundRequest{Amount: 2000, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"}))
+}
+
+func (suite *RefundTrackerTestSuite) TestCheckRefundExceedsTotalCredit() {
+	err := suite.testable.CheckRefund(&RefundRequest{Amount: 2000.5, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "RefundTracker.CheckRefund error: refund amount 2000.5 exceeds available amount 2000", err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestCheckRefundInvalidRequest() {
+	err := suite.testable.CheckRefund(&RefundRequest{InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RefundTracker.CheckRefund error: parameter "amount" is empty`, err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestCheckRefundUnknownCollection() {
+	err := suite.testable.CheckRefund(&RefundRequest{Amount: 1, InternalReference: "foo"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RefundTracker.CheckRefund error: collection "foo" is not tracked`, err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestReserveRefund() {
+	reservation, err := suite.testable.ReserveRefund(&RefundRequest{Amount: 1500, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "reservation-1", reservation.ID)
+	balance, _ := suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(1500), balance.Pending)
+
+	//the concurrent refund does not fit the rest
+	_, err = suite.testable.ReserveRefund(&RefundRequest{Amount: 1000, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "RefundTracker.ReserveRefund error: refund amount 1000 exceeds available amount 500", err.Error())
+
+	data := &RefundResponseData{InternalReference: "RFD-1", CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX", RefundCurrency: "UGX", RefundAmount: 1500, TransactionStatus: "PENDING"}
+	assert.NoError(suite.T(), suite.testable.ConfirmReservation(reservation.ID, data))
+	balance, _ = suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(1500), balance.Pending)
+
+	webhook := &RefundWebhook{InternalReference: "RFD-1", CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX", RefundCurrency: "UGX", RefundAmount: 1500, TransactionStatus: "COMPLETED"}
+	assert.NoError(suite.T(), suite.testable.TrackRefundWebhook(webhook))
+	balance, _ = suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(0), balance.Pending)
+	assert.Equal(suite.T(), float64(1500), balance.Refunded)
+
+	err = suite.testable.ConfirmReservation(reservation.ID, data)
+	assert.Equal(suite.T(), `RefundTracker.ConfirmReservation error: reservation "reservation-1" not found`, err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestReleaseReservation() {
+	reservation, _ := suite.testable.ReserveRefund(&RefundRequest{Amount: 1500, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	assert.NoError(suite.T(), suite.testable.ReleaseReservation(reservation.ID))
+	balance, _ := suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(0), balance.Pending)
+	assert.Equal(suite.T(), float64(2000), balance.Available())
+	err := suite.testable.ReleaseReservation(reservation.ID)
+	assert.Equal(suite.T(), `RefundTracker.ReleaseReservation error: reservation "reservation-1" not found`, err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestConfirmReservationErrorKeepsReservation() {
+	reservation, _ := suite.testable.ReserveRefund(&RefundRequest{Amount: 1500, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	data := &RefundResponseData{InternalReference: "RFD-1", CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX", RefundCurrency: "KES", RefundAmount: 1500, TransactionStatus: "PENDING"}
+	err := suite.testable.ConfirmReservation(reservation.ID, data)
+	assert.Equal(suite.T(), `RefundTracker.ConfirmReservation error: RefundTracker.TrackRefund error: refund currency "KES" differs from collection currency "UGX"`, err.Error())
+	balance, _ := suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(1500), balance.Pending)
+
+	data.CollectionReference = "DUSUPAYOTHER"
+	err = suite.testable.ConfirmReservation(reservation.ID, data)
+	assert.Equal(suite.T(), `RefundTracker.ConfirmReservation error: refund collection "DUSUPAYOTHER" differs from reserved collection "DUSUPAYXYXYXYXYXYXYXYXYX"`, err.Error())
+	assert.NoError(suite.T(), suite.testable.ReleaseReservation(reservation.ID))
+}
+
+func (suite *RefundTrackerTestSuite) TestReserveRefundErrors() {
+	_, err := suite.testable.ReserveRefund(&RefundRequest{InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"})
+	assert.Equal(suite.T(), `RefundTracker.ReserveRefund error: parameter "amount" is empty`, err.Error())
+	_, err = suite.testable.ReserveRefund(&RefundRequest{Amount: 1, InternalReference: "foo"})
+	assert.Equal(suite.T(), `RefundTracker.ReserveRefund error: collection "foo" is not tracked`, err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestTrackRefundLifecycle() {
+	var response RefundResponse
+	body, _ := LoadStubResponseData("stubs/refunds/create/success.json")
+	_ = json.Unmarshal(body, &response)
+	//pending refund
+	assert.NoError(suite.T(), suite.testable.TrackRefund(response.Data))
+	balance, ok := suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), float64(0), balance.Refunded)
+	assert.Equal(suite.T(), float64(1054), balance.Pending)
+	assert.Equal(suite.T(), float64(946), balance.Available())
+	assert.Error(suite.T(), suite.testable.CheckRefund(&RefundRequest{Amount: 1000, InternalReference: "DUSUPAYXYXYXYXYXYXYXYXYX"}))
+	//completed refund
+	var webhook RefundWebhook
+	body, _ = LoadStubResponseData("stubs/webhooks/request/refund-success.json")
+	_ = json.Unmarshal(body, &webhook)
+	assert.NoError(suite.T(), suite.testable.TrackRefundWebhook(&webhook))
+	balance, _ = suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(1054), balance.Refunded)
+	assert.Equal(suite.T(), float64(0), balance.Pending)
+	assert.Equal(suite.T(), float64(946), balance.Available())
+}
+
+func (suite *RefundTrackerTestSuite) TestTrackRefundFailedReleasesAmount() {
+	data := &RefundResponseData{InternalReference: "RFD-1", CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX", RefundCurrency: "UGX", RefundAmount: 500, TransactionStatus: "PENDING"}
+	assert.NoError(suite.T(), suite.testable.TrackRefund(data))
+	webhook := &RefundWebhook{InternalReference: "RFD-1", CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX", RefundCurrency: "UGX", RefundAmount: 500, TransactionStatus: "FAILED"}
+	assert.NoError(suite.T(), suite.testable.TrackRefundWebhook(webhook))
+	balance, _ := suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(0), balance.Pending)
+	assert.Equal(suite.T(), float64(2000), balance.Available())
+}
+
+func (suite *RefundTrackerTestSuite) TestTrackRefundErrors() {
+	err := suite.testable.TrackRefund(&RefundResponseData{CollectionReference: "foo"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RefundTracker.TrackRefund error: collection "foo" is not tracked`, err.Error())
+	err = suite.testable.TrackRefund(&RefundResponseData{CollectionReference: "DUSUPAYXYXYXYXYXYXYXYXYX", RefundCurrency: "USD"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `RefundTracker.TrackRefund error: refund currency "USD" differs from collection currency "UGX"`, err.Error())
+}
+
+func (suite *RefundTrackerTestSuite) TestTrackCollectionWebhook() {
+	var webhook CollectionWebhook
+	body, _ := LoadStubResponseData("stubs/webhooks/request/collection-success.json")
+	_ = json.Unmarshal(body, &webhook)
+	suite.testable.TrackCollectionWebhook(&webhook)
+	balance, ok := suite.testable.GetBalance("DUSUPAY405GZM1G5JXGA71IK")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), "UGX", balance.Currency)
+	assert.Equal(suite.T(), 716.5916, balance.TotalCredit)
+
+	webhook.InternalReference = "pending"
+	webhook.TransactionStatus = "PENDING"
+	suite.testable.TrackCollectionWebhook(&webhook)
+	_, ok = suite.testable.GetBalance("pending")
+	assert.False(suite.T(), ok)
+}
+
+func (suite *RefundTrackerTestSuite) TestTrackCollectionUpdate() {
+	suite.testable.TrackCollection("DUSUPAYXYXYXYXYXYXYXYXYX", "UGX", 3000)
+	balance, _ := suite.testable.GetBalance("DUSUPAYXYXYXYXYXYXYXYXYX")
+	assert.Equal(suite.T(), float64(3000), balance.TotalCredit)
+}
+
+func TestRefundTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundTrackerTestSuite))
+}
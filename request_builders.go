@@ -0,0 +1,367 @@
+package dusupay
+
+//normalizeMobileAccount normalize phone number by the provider country numbers plan (numbers of the countries without the plan are kept as is)
+func normalizeMobileAccount(errs *ValidationErrors, accountNumber string, providerId string) string {
+	country := providerCountry(providerId)
+	if _, ok := msisdnRules[country]; !ok || accountNumber == "" {
+		return accountNumber
+	}
+	m, err := ParseMSISDN(accountNumber, country)
+	if err != nil {
+		errs.addIf(true, "account_number", ValidationReasonBadFormat, "is not valid %s mobile number", country)
+		return accountNumber
+	}
+	return m.AccountNumber()
+}
+
+//MobileMoneyCollection create mobile money collection request builder
+func MobileMoneyCollection() *MobileMoneyCollectionBuilder {
+	return &MobileMoneyCollectionBuilder{req: CollectionRequest{Method: TransactionMethodMobileMoney}}
+}
+
+//MobileMoneyCollectionBuilder mobile money collection request fluent builder
+type MobileMoneyCollectionBuilder struct {
+	req CollectionRequest
+}
+
+//SetAmount set collection currency and amount
+func (b *MobileMoneyCollectionBuilder) SetAmount(currency CurrencyCode, amount float64) *MobileMoneyCollectionBuilder {
+	b.req.Currency = currency
+	b.req.Amount = amount
+	return b
+}
+
+//SetProvider set mobile money provider id (e.g. mtn_ug)
+func (b *MobileMoneyCollectionBuilder) SetProvider(providerId string) *MobileMoneyCollectionBuilder {
+	b.req.ProviderId = providerId
+	return b
+}
+
+//SetReference set merchant reference and narration
+func (b *MobileMoneyCollectionBuilder) SetReference(merchantReference string, narration string) *MobileMoneyCollectionBuilder {
+	b.req.MerchantReference = merchantReference
+	b.req.Narration = narration
+	return b
+}
+
+//SetPhone set customer phone number (any local or international format, it is normalized by the provider country on Build)
+func (b *MobileMoneyCollectionBuilder) SetPhone(accountNumber string) *MobileMoneyCollectionBuilder {
+	b.req.AccountNumber = accountNumber
+	return b
+}
+
+//SetCustomer set customer name and email
+func (b *MobileMoneyCollectionBuilder) SetCustomer(name string, email string) *MobileMoneyCollectionBuilder {
+	b.req.AccountName = name
+	b.req.AccountEmail = email
+	return b
+}
+
+//UseHostedPaymentPage collect through the hosted payment page (the phone number is entered by the customer)
+func (b *MobileMoneyCollectionBuilder) UseHostedPaymentPage(redirectUrl string) *MobileMoneyCollectionBuilder {
+	b.req.UseMobileMoneyHpp(redirectUrl)
+	return b
+}
+
+//Build validate and build collection request (all validation errors are returned as ValidationErrors)
+func (b *MobileMoneyCollectionBuilder) Build() (*CollectionRequest, error) {
+	req := b.req
+	var errs ValidationErrors
+	req.AccountNumber = normalizeMobileAccount(&errs, req.AccountNumber, req.ProviderId)
+	errs = append(req.Validate(), errs...)
+	err := errs.orNil()
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+//CardCollection create card collection request builder
+func CardCollection() *RedirectCollectionBuilder {
+	return &RedirectCollectionBuilder{req: CollectionRequest{Method: TransactionMethodCard}}
+}
+
+//BankCollection create bank collection request builder
+func BankCollection() *RedirectCollectionBuilder {
+	return &RedirectCollectionBuilder{req: CollectionRequest{Method: TransactionMethodBank}}
+}
+
+//RedirectCollectionBuilder card or bank collection request fluent builder (the customer is redirected to the payment page)
+type RedirectCollectionBuilder struct {
+	req CollectionRequest
+}
+
+//SetAmount set collection currency and amount
+func (b *RedirectCollectionBuilder) SetAmount(currency CurrencyCode, amount float64) *RedirectCollectionBuilder {
+	b.req.Currency = currency
+	b.req.Amount = amount
+	return b
+}
+
+//SetProvider set provider id
+func (b *RedirectCollectionBuilder) SetProvider(providerId string) *RedirectCollectionBuilder {
+	b.req.ProviderId = providerId
+	return b
+}
+
+//SetReference set merchant reference and narration
+func (b *RedirectCollectionBuilder) SetReference(merchantReference string, narration string) *RedirectCollectionBuilder {
+	b.req.MerchantReference = merchantReference
+	b.req.Narration = narration
+	return b
+}
+
+//SetRedirectUrl set url the customer is redirected to after the payment
+func (b *RedirectCollectionBuilder) SetRedirectUrl(redirectUrl string) *RedirectCollectionBuilder {
+	b.req.RedirectUrl = redirectUrl
+	return b
+}
+
+//SetCustomer set customer name and email
+func (b *RedirectCollectionBuilder) SetCustomer(name string, email string) *RedirectCollectionBuilder {
+	b.req.AccountName = name
+	b.req.AccountEmail = email
+	return b
+}
+
+//Build validate and build collection request (all validation errors are returned as ValidationErrors)
+func (b *RedirectCollectionBuilder) Build() (*CollectionRequest, error) {
+	req := b.req
+	err := req.Validate().orNil()
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+//CryptoCollection create crypto collection request builder
+func CryptoCollection() *CryptoCollectionBuilder {
+	return &CryptoCollectionBuilder{req: CollectionRequest{Method: TransactionMethodCrypto}}
+}
+
+//CryptoCollectionBuilder crypto collection request fluent builder
+type CryptoCollectionBuilder struct {
+	req CollectionRequest
+}
+
+//SetAmount set collection currency and amount
+func (b *CryptoCollectionBuilder) SetAmount(currency CurrencyCode, amount float64) *CryptoCollectionBuilder {
+	b.req.Currency = currency
+	b.req.Amount = amount
+	return b
+}
+
+//SetProvider set crypto provider id
+func (b *CryptoCollectionBuilder) SetProvider(providerId string) *CryptoCollectionBuilder {
+	b.req.ProviderId = providerId
+	return b
+}
+
+//SetReference set merchant reference and narration
+func (b *CryptoCollectionBuilder) SetReference(merchantReference string, narration string) *CryptoCollectionBuilder {
+	b.req.MerchantReference = merchantReference
+	b.req.Narration = narration
+	return b
+}
+
+//SetAsset set crypto asset and its network
+func (b *CryptoCollectionBuilder) SetAsset(asset CryptoAssetCode, network CryptoNetworkCode) *CryptoCollectionBuilder {
+	b.req.Crypto = &CryptoParams{Asset: asset, Network: network}
+	return b
+}
+
+//SetRedirectUrl set url the customer is redirected to after the payment
+func (b *CryptoCollectionBuilder) SetRedirectUrl(redirectUrl string) *CryptoCollectionBuilder {
+	b.req.RedirectUrl = redirectUrl
+	return b
+}
+
+//SetCustomer set customer name and email
+func (b *CryptoCollectionBuilder) SetCustomer(name string, email string) *CryptoCollectionBuilder {
+	b.req.AccountName = name
+	b.req.AccountEmail = email
+	return b
+}
+
+//Build validate and build collection request (all validation errors are returned as ValidationErrors)
+func (b *CryptoCollectionBuilder) Build() (*CollectionRequest, error) {
+	req := b.req
+	err := req.Validate().orNil()
+	if err != nil {
+		return nil, err
+	}
+	crypto := *req.Crypto
+	req.Crypto = &crypto
+	return &req, nil
+}
+
+//MobileMoneyPayout create mobile money payout request builder
+func MobileMoneyPayout() *MobileMoneyPayoutBuilder {
+	return &MobileMoneyPayoutBuilder{req: PayoutRequest{Method: TransactionMethodMobileMoney}}
+}
+
+//MobileMoneyPayoutBuilder mobile money payout request fluent builder
+type MobileMoneyPayoutBuilder struct {
+	req PayoutRequest
+}
+
+//SetAmount set payout currency and amount
+func (b *MobileMoneyPayoutBuilder) SetAmount(currency CurrencyCode, amount float64) *MobileMoneyPayoutBuilder {
+	b.req.Currency = currency
+	b.req.Amount = amount
+	return b
+}
+
+//SetProvider set mobile money provider id (e.g. mtn_ug)
+func (b *MobileMoneyPayoutBuilder) SetProvider(providerId string) *MobileMoneyPayoutBuilder {
+	b.req.ProviderId = providerId
+	return b
+}
+
+//SetReference set merchant reference and narration
+func (b *MobileMoneyPayoutBuilder) SetReference(merchantReference string, narration string) *MobileMoneyPayoutBuilder {
+	b.req.MerchantReference = merchantReference
+	b.req.Narration = narration
+	return b
+}
+
+//SetBeneficiary set beneficiary phone number (normalized by the provider country on Build) and name
+func (b *MobileMoneyPayoutBuilder) SetBeneficiary(accountNumber string, name string) *MobileMoneyPayoutBuilder {
+	b.req.AccountNumber = accountNumber
+	b.req.AccountName = name
+	return b
+}
+
+//SetEmail set beneficiary email
+func (b *MobileMoneyPayoutBuilder) SetEmail(email string) *MobileMoneyPayoutBuilder {
+	b.req.AccountEmail = email
+	return b
+}
+
+//Build validate and build payout request (all validation errors are returned as ValidationErrors)
+func (b *MobileMoneyPayoutBuilder) Build() (*PayoutRequest, error) {
+	req := b.req
+	var errs ValidationErrors
+	req.AccountNumber = normalizeMobileAccount(&errs, req.AccountNumber, req.ProviderId)
+	errs = append(req.Validate(), errs...)
+	err := errs.orNil()
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+//CryptoPayout create crypto payout request builder
+func CryptoPayout() *CryptoPayoutBuilder {
+	return &CryptoPayoutBuilder{req: PayoutRequest{Method: TransactionMethodCrypto}}
+}
+
+//CryptoPayoutBuilder crypto payout request fluent builder
+type CryptoPayoutBuilder struct {
+	req PayoutRequest
+}
+
+//SetAmount set payout currency and amount
+func (b *CryptoPayoutBuilder) SetAmount(currency CurrencyCode, amount float64) *CryptoPayoutBuilder {
+	b.req.Currency = currency
+	b.req.Amount = amount
+	return b
+}
+
+//SetProvider set crypto provider id
+func (b *CryptoPayoutBuilder) SetProvider(providerId string) *CryptoPayoutBuilder {
+	b.req.ProviderId = providerId
+	return b
+}
+
+//SetReference set merchant reference and narration
+func (b *CryptoPayoutBuilder) SetReference(merchantReference string, narration string) *CryptoPayoutBuilder {
+	b.req.MerchantReference = merchantReference
+	b.req.Narration = narration
+	return b
+}
+
+//SetWallet set beneficiary wallet address, crypto asset and its network
+func (b *CryptoPayoutBuilder) SetWallet(address string, asset CryptoAssetCode, network CryptoNetworkCode) *CryptoPayoutBuilder {
+	b.req.AccountNumber = address
+	b.req.Crypto = &CryptoParams{Asset: asset, Network: network}
+	return b
+}
+
+//SetBeneficiary set beneficiary name and email (optional)
+func (b *CryptoPayoutBuilder) SetBeneficiary(name string, email string) *CryptoPayoutBuilder {
+	b.req.AccountName = name
+	b.req.AccountEmail = email
+	return b
+}
+
+//Build validate and build payout request (all validation errors are returned as ValidationErrors)
+func (b *CryptoPayoutBuilder) Build() (*PayoutRequest, error) {
+	err := b.req.Validate().orNil()
+	if err != nil {
+		return nil, err
+	}
+	return copyPayoutRequest(&b.req), nil
+}
+
+//BankPayout create bank payout request builder
+func BankPayout() *BankPayoutBuilder {
+	return &BankPayoutBuilder{req: PayoutRequest{Method: TransactionMethodBank}}
+}
+
+//BankPayoutBuilder bank payout request fluent builder
+type BankPayoutBuilder struct {
+	req PayoutRequest
+}
+
+//SetAmount set payout currency and amount
+func (b *BankPayoutBuilder) SetAmount(currency CurrencyCode, amount float64) *BankPayoutBuilder {
+	b.req.Currency = currency
+	b.req.Amount = amount
+	return b
+}
+
+//SetProvider set bank provider id (e.g. bank_ng)
+func (b *BankPayoutBuilder) SetProvider(providerId string) *BankPayoutBuilder {
+	b.req.ProviderId = providerId
+	return b
+}
+
+//SetReference set merchant reference and narration
+func (b *BankPayoutBuilder) SetReference(merchantReference string, narration string) *BankPayoutBuilder {
+	b.req.MerchantReference = merchantReference
+	b.req.Narration = narration
+	return b
+}
+
+//SetBeneficiary set beneficiary bank account number and name
+func (b *BankPayoutBuilder) SetBeneficiary(accountNumber string, name string) *BankPayoutBuilder {
+	b.req.AccountNumber = accountNumber
+	b.req.AccountName = name
+	return b
+}
+
+//SetEmail set beneficiary email
+func (b *BankPayoutBuilder) SetEmail(email string) *BankPayoutBuilder {
+	b.req.AccountEmail = email
+	return b
+}
+
+//SetBank set beneficiary bank and branch codes (see BanksResource)
+func (b *BankPayoutBuilder) SetBank(bankCode string, branchCode string) *BankPayoutBuilder {
+	b.req.ExtraParams.BankCode = bankCode
+	b.req.ExtraParams.BankBranchCode = branchCode
+	return b
+}
+
+//Build validate and build payout request (all validation errors are returned as ValidationErrors)
+func (b *BankPayoutBuilder) Build() (*PayoutRequest, error) {
+	req := b.req
+	errs := req.Validate()
+	errs.addIf(req.ExtraParams.BankCode == "", "extra_params.bank_code", ValidationReasonMissing, "is empty")
+	err := errs.orNil()
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
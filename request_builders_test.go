@@ -0,0 +1,172 @@
+package dusupay
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RequestBuildersTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RequestBuildersTestSuite) assertValidationErrors(err error, fields ...string) {
+	var errs ValidationErrors
+	assert.True(suite.T(), errors.As(err, &errs))
+	result := make([]string, len(errs))
+	for i, e := range errs {
+		result[i] = e.Field
+	}
+	assert.Equal(suite.T(), fields, result)
+}
+
+func (suite *RequestBuildersTestSuite) TestMobileMoneyCollectionSuccess() {
+	result, err := MobileMoneyCollection().
+		SetAmount(CurrencyCodeUGX, 10000).
+		SetProvider("mtn_ug").
+		SetPhone("256777000123").
+		SetReference("ref-1", "Order #1").
+		SetCustomer("John Doe", "john@example.com").
+		Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodMobileMoney, result.Method)
+	assert.Equal(suite.T(), CurrencyCodeUGX, result.Currency)
+	assert.Equal(suite.T(), float64(10000), result.Amount)
+	assert.Equal(suite.T(), "mtn_ug", result.ProviderId)
+	assert.Equal(suite.T(), "256777000123", result.AccountNumber)
+	assert.Equal(suite.T(), "ref-1", result.MerchantReference)
+	assert.Equal(suite.T(), "Order #1", result.Narration)
+	assert.Equal(suite.T(), "john@example.com", result.AccountEmail)
+	assert.Empty(suite.T(), result.RedirectUrl)
+}
+
+func (suite *RequestBuildersTestSuite) TestMobileMoneyCollectionHostedPaymentPage() {
+	builder := MobileMoneyCollection().SetAmount(CurrencyCodeUGX, 10000).SetProvider("mtn_ug").SetReference("ref-1", "Order #1")
+	_, err := builder.Build()
+	suite.assertValidationErrors(err, "account_number")
+
+	result, err := builder.UseHostedPaymentPage("https://www.sample-url.com/redirect").Build()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.MobileMoneyHpp)
+	assert.Equal(suite.T(), "https://www.sample-url.com/redirect", result.RedirectUrl)
+}
+
+func (suite *RequestBuildersTestSuite) TestMobileMoneyCollectionAllErrors() {
+	result, err := MobileMoneyCollection().Build()
+	assert.Nil(suite.T(), result)
+	suite.assertValidationErrors(err, "currency", "amount", "provider_id", "merchant_reference", "narration", "account_number")
+	assert.Equal(suite.T(), `parameter "currency" is empty; parameter "amount" is empty; parameter "provider_id" is empty; `+
+		`parameter "merchant_reference" is empty; parameter "narration" is empty; parameter "account_number" is empty`, err.Error())
+}
+
+func (suite *RequestBuildersTestSuite) TestCardCollection() {
+	builder := CardCollection().SetAmount(CurrencyCodeUSD, 10).SetProvider("international_usd").SetReference("ref-1", "Order #1")
+	_, err := builder.Build()
+	suite.assertValidationErrors(err, "redirect_url")
+
+	result, err := builder.SetRedirectUrl("https://www.sample-url.com/redirect").SetCustomer("John Doe", "john@example.com").Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodCard, result.Method)
+	assert.Equal(suite.T(), "https://www.sample-url.com/redirect", result.RedirectUrl)
+	assert.Equal(suite.T(), "John Doe", result.AccountName)
+}
+
+func (suite *RequestBuildersTestSuite) TestBankCollection() {
+	result, err := BankCollection().SetAmount(CurrencyCodeNGN, 1000).SetProvider("bank_ng").SetReference("ref-1", "Order #1").
+		SetRedirectUrl("https://www.sample-url.com/redirect").Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodBank, result.Method)
+}
+
+func (suite *RequestBuildersTestSuite) TestMobileMoneyPayout() {
+	builder := MobileMoneyPayout().SetAmount(CurrencyCodeUGX, 5000).SetProvider("mtn_ug").SetReference("ref-1", "Salary")
+	_, err := builder.Build()
+	suite.assertValidationErrors(err, "account_number", "account_name")
+
+	result, err := builder.SetBeneficiary("256777000123", "John Doe").SetEmail("john@example.com").Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodMobileMoney, result.Method)
+	assert.Equal(suite.T(), "256777000123", result.AccountNumber)
+	assert.Equal(suite.T(), "John Doe", result.AccountName)
+	assert.Equal(suite.T(), "john@example.com", result.AccountEmail)
+}
+
+func (suite *RequestBuildersTestSuite) TestMobileMoneyPhoneNormalized() {
+	collection, err := MobileMoneyCollection().SetAmount(CurrencyCodeUGX, 10000).SetProvider("mtn_ug").
+		SetPhone("0777 000 123").SetReference("ref-1", "Order #1").Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "256777000123", collection.AccountNumber)
+
+	payout, err := MobileMoneyPayout().SetAmount(CurrencyCodeUGX, 5000).SetProvider("mtn_ug").SetReference("ref-1", "Salary").
+		SetBeneficiary("+256 777-000-123", "John Doe").Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "256777000123", payout.AccountNumber)
+}
+
+func (suite *RequestBuildersTestSuite) TestMobileMoneyPhoneInvalid() {
+	_, err := MobileMoneyCollection().SetAmount(CurrencyCodeUGX, 10000).SetProvider("mtn_ug").
+		SetPhone("254712345678").SetReference("ref-1", "Order #1").Build()
+	suite.assertValidationErrors(err, "account_number")
+	assert.Equal(suite.T(), `parameter "account_number" is not valid UG mobile number`, err.Error())
+
+	_, err = MobileMoneyPayout().SetAmount(CurrencyCodeUGX, 5000).SetProvider("mtn_ug").SetReference("ref-1", "Salary").
+		SetBeneficiary("0777", "John Doe").Build()
+	suite.assertValidationErrors(err, "account_number")
+}
+
+func (suite *RequestBuildersTestSuite) TestCryptoCollection() {
+	builder := CryptoCollection().SetAmount(CurrencyCodeUSD, 10).SetProvider("crypto").SetReference("ref-1", "Order #1").
+		SetRedirectUrl("https://www.sample-url.com/redirect")
+	_, err := builder.Build()
+	suite.assertValidationErrors(err, "crypto")
+
+	result, err := builder.SetAsset(CryptoAssetUSDT, CryptoNetworkTron).Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodCrypto, result.Method)
+	assert.Equal(suite.T(), &CryptoParams{Asset: CryptoAssetUSDT, Network: CryptoNetworkTron}, result.Crypto)
+
+	_, err = builder.SetAsset(CryptoAssetBTC, CryptoNetworkTron).Build()
+	suite.assertValidationErrors(err, "crypto.asset")
+}
+
+func (suite *RequestBuildersTestSuite) TestCryptoPayout() {
+	builder := CryptoPayout().SetAmount(CurrencyCodeUSD, 10).SetProvider("crypto").SetReference("ref-1", "Withdrawal")
+	_, err := builder.Build()
+	suite.assertValidationErrors(err, "account_number", "crypto")
+
+	result, err := builder.SetWallet("0x71C7656EC7ab88b098defB751B7401B5f6d8976F", CryptoAssetUSDC, CryptoNetworkEthereum).Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodCrypto, result.Method)
+	assert.Equal(suite.T(), "0x71C7656EC7ab88b098defB751B7401B5f6d8976F", result.AccountNumber)
+	assert.Equal(suite.T(), &CryptoParams{Asset: CryptoAssetUSDC, Network: CryptoNetworkEthereum}, result.Crypto)
+
+	_, err = builder.SetWallet("TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", CryptoAssetUSDC, CryptoNetworkEthereum).Build()
+	suite.assertValidationErrors(err, "account_number")
+}
+
+func (suite *RequestBuildersTestSuite) TestBankPayout() {
+	builder := BankPayout().SetAmount(CurrencyCodeNGN, 1000).SetProvider("bank_ng").SetReference("ref-1", "Salary").
+		SetBeneficiary("0123456789", "John Doe")
+	_, err := builder.Build()
+	suite.assertValidationErrors(err, "extra_params.bank_code")
+
+	result, err := builder.SetBank("access_bank", "001").Build()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), TransactionMethodBank, result.Method)
+	assert.Equal(suite.T(), "access_bank", result.ExtraParams.BankCode)
+	assert.Equal(suite.T(), "001", result.ExtraParams.BankBranchCode)
+}
+
+func (suite *RequestBuildersTestSuite) TestBuildReturnsCopy() {
+	builder := MobileMoneyPayout().SetAmount(CurrencyCodeUGX, 5000).SetProvider("mtn_ug").SetReference("ref-1", "Salary").
+		SetBeneficiary("256777000123", "John Doe")
+	first, _ := builder.Build()
+	second, _ := builder.SetReference("ref-2", "Salary").Build()
+	assert.Equal(suite.T(), "ref-1", first.MerchantReference)
+	assert.Equal(suite.T(), "ref-2", second.MerchantReference)
+}
+
+func TestRequestBuildersTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestBuildersTestSuite))
+}
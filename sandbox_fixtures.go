@@ -0,0 +1,148 @@
+package dusupay
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//SandboxScenario sandbox test transaction outcome
+type SandboxScenario string
+
+//SandboxScenarioSuccess transaction completes successfully
+const SandboxScenarioSuccess SandboxScenario = "success"
+
+//SandboxScenarioFailure transaction fails
+const SandboxScenarioFailure SandboxScenario = "failure"
+
+//DefaultSandboxFixturesName default account holder name and narration of the fixtures
+const DefaultSandboxFixturesName = "Sandbox Test"
+
+//pickSandboxTestAccount pick test account for the scenario
+func pickSandboxTestAccount(scenario SandboxScenario, success string, failure string) (string, error) {
+	var account string
+	switch scenario {
+	case SandboxScenarioSuccess:
+		account = success
+	case SandboxScenarioFailure:
+		account = failure
+	default:
+		return "", fmt.Errorf(`sandbox scenario "%s" is invalid`, scenario)
+	}
+	if account == "" {
+		return "", fmt.Errorf(`sandbox test account for "%s" scenario is empty`, scenario)
+	}
+	return account, nil
+}
+
+//GetSandboxTestAccount get provider test account for the scenario
+func (item *ProvidersResponseDataItem) GetSandboxTestAccount(scenario SandboxScenario) (string, error) {
+	return pickSandboxTestAccount(scenario, item.SandboxTestAccounts.Success, item.SandboxTestAccounts.Failure)
+}
+
+//GetSandboxTestAccount get bank test account for the scenario
+func (item *BanksResponseDataItem) GetSandboxTestAccount(scenario SandboxScenario) (string, error) {
+	return pickSandboxTestAccount(scenario, item.SandboxTestAccounts.Success, item.SandboxTestAccounts.Failure)
+}
+
+//NewSandboxFixtures create new sandbox requests fixtures builder (sandbox config only)
+func NewSandboxFixtures(cfg *Config) (*SandboxFixtures, error) {
+	if !cfg.IsSandbox() {
+		return nil, fmt.Errorf("NewSandboxFixtures error: fixtures are allowed in sandbox environment only")
+	}
+	return &SandboxFixtures{
+		ReferencePrefix: "sandbox",
+		RedirectUrl:     "https://www.sample-url.com/redirect",
+		Name:            DefaultSandboxFixturesName,
+		Now:             time.Now,
+	}, nil
+}
+
+//SandboxFixtures ready-to-send sandbox scenario requests builder
+type SandboxFixtures struct {
+	ReferencePrefix string
+	RedirectUrl     string
+	//account holder name and narration
+	Name     string
+	Now      func() time.Time
+	mu       sync.Mutex
+	sequence int
+}
+
+//buildReference build unique merchant reference
+func (sf *SandboxFixtures) buildReference(scenario SandboxScenario) string {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.sequence++
+	return fmt.Sprintf("%s-%s-%d-%d", sf.ReferencePrefix, scenario, sf.Now().UnixNano(), sf.sequence)
+}
+
+//Collection build provider collection request for the scenario (zero amount means the provider min amount)
+func (sf *SandboxFixtures) Collection(method TransactionMethodCode, provider *ProvidersResponseDataItem, scenario SandboxScenario, amount float64) (*CollectionRequest, error) {
+	account, err := provider.GetSandboxTestAccount(scenario)
+	if err != nil {
+		return nil, fmt.Errorf(`SandboxFixtures.Collection error: provider "%s": %v`, provider.ID, err)
+	}
+	if amount == 0 {
+		amount = provider.MinAmount
+	}
+	req := &CollectionRequest{
+		Currency:          CurrencyCode(provider.TransactionCurrency),
+		Amount:            amount,
+		Method:            method,
+		ProviderId:        provider.ID,
+		AccountNumber:     account,
+		AccountName:       sf.Name,
+		MerchantReference: sf.buildReference(scenario),
+		Narration:         sf.Name,
+	}
+	if method != TransactionMethodMobileMoney {
+		req.RedirectUrl = sf.RedirectUrl
+	}
+	return req, nil
+}
+
+//Payout build provider payout request for the scenario (zero amount means the provider min amount)
+func (sf *SandboxFixtures) Payout(method TransactionMethodCode, provider *ProvidersResponseDataItem, scenario SandboxScenario, amount float64) (*PayoutRequest, error) {
+	account, err := provider.GetSandboxTestAccount(scenario)
+	if err != nil {
+		return nil, fmt.Errorf(`SandboxFixtures.Payout error: provider "%s": %v`, provider.ID, err)
+	}
+	if amount == 0 {
+		amount = provider.MinAmount
+	}
+	return &PayoutRequest{
+		Currency:          CurrencyCode(provider.TransactionCurrency),
+		Amount:            amount,
+		Method:            method,
+		ProviderId:        provider.ID,
+		AccountNumber:     account,
+		AccountName:       sf.Name,
+		MerchantReference: sf.buildReference(scenario),
+		Narration:         sf.Name,
+	}, nil
+}
+
+//BankPayout build bank payout request for the scenario (zero amount means the bank min amount)
+func (sf *SandboxFixtures) BankPayout(bank *BanksResponseDataItem, branchCode string, scenario SandboxScenario, amount float64) (*PayoutRequest, error) {
+	account, err := bank.GetSandboxTestAccount(scenario)
+	if err != nil {
+		return nil, fmt.Errorf(`SandboxFixtures.BankPayout error: bank "%s": %v`, bank.BankCode, err)
+	}
+	if amount == 0 {
+		amount = bank.MinAmount
+	}
+	req := &PayoutRequest{
+		Currency:          CurrencyCode(bank.TransactionCurrency),
+		Amount:            amount,
+		Method:            TransactionMethodBank,
+		ProviderId:        bank.Id,
+		AccountNumber:     account,
+		AccountName:       sf.Name,
+		MerchantReference: sf.buildReference(scenario),
+		Narration:         sf.Name,
+	}
+	req.ExtraParams.BankCode = bank.BankCode
+	req.ExtraParams.BankBranchCode = branchCode
+	return req, nil
+}
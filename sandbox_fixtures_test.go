@@ -0,0 +1,101 @@
+package dusupay
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SandboxFixturesTestSuite struct {
+	suite.Suite
+	providers *ProvidersResponse
+	banks     *BanksResponse
+	testable  *SandboxFixtures
+}
+
+func (suite *SandboxFixturesTestSuite) SetupTest() {
+	body, _ := LoadStubResponseData("stubs/providers/payment-options/success-sandbox.json")
+	suite.providers = &ProvidersResponse{}
+	_ = json.Unmarshal(body, suite.providers)
+	body, _ = LoadStubResponseData("stubs/banks/list/success-sandbox.json")
+	suite.banks = &BanksResponse{}
+	_ = json.Unmarshal(body, suite.banks)
+	suite.testable, _ = NewSandboxFixtures(BuildStubConfig())
+	suite.testable.Now = func() time.Time { return time.Unix(0, 100) }
+}
+
+func (suite *SandboxFixturesTestSuite) TestGetSandboxTestAccount() {
+	provider := (*suite.providers.Data)[0]
+	account, err := provider.GetSandboxTestAccount(SandboxScenarioSuccess)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "256777000123", account)
+	account, err = provider.GetSandboxTestAccount(SandboxScenarioFailure)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "256777000456", account)
+
+	_, err = provider.GetSandboxTestAccount("timeout")
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `sandbox scenario "timeout" is invalid`, err.Error())
+
+	_, err = (&BanksResponseDataItem{}).GetSandboxTestAccount(SandboxScenarioSuccess)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `sandbox test account for "success" scenario is empty`, err.Error())
+}
+
+func (suite *SandboxFixturesTestSuite) TestNewSandboxFixturesProduction() {
+	result, err := NewSandboxFixtures(NewConfig("foo", "bar"))
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), "NewSandboxFixtures error: fixtures are allowed in sandbox environment only", err.Error())
+}
+
+func (suite *SandboxFixturesTestSuite) TestCollection() {
+	result, err := suite.testable.Collection(TransactionMethodMobileMoney, (*suite.providers.Data)[1], SandboxScenarioFailure, 0)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), result.isValid())
+	assert.Equal(suite.T(), CurrencyCodeUGX, result.Currency)
+	assert.Equal(suite.T(), float64(3000), result.Amount)
+	assert.Equal(suite.T(), "airtel_ug", result.ProviderId)
+	assert.Equal(suite.T(), "256752000456", result.AccountNumber)
+	assert.Equal(suite.T(), "sandbox-failure-100-1", result.MerchantReference)
+	assert.Empty(suite.T(), result.RedirectUrl)
+
+	result, _ = suite.testable.Collection(TransactionMethodCard, (*suite.providers.Data)[1], SandboxScenarioSuccess, 5000)
+	assert.Equal(suite.T(), float64(5000), result.Amount)
+	assert.Equal(suite.T(), "sandbox-success-100-2", result.MerchantReference)
+	assert.Equal(suite.T(), suite.testable.RedirectUrl, result.RedirectUrl)
+}
+
+func (suite *SandboxFixturesTestSuite) TestCollectionError() {
+	result, err := suite.testable.Collection(TransactionMethodMobileMoney, &ProvidersResponseDataItem{ID: "mtn_ug"}, SandboxScenarioSuccess, 0)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `SandboxFixtures.Collection error: provider "mtn_ug": sandbox test account for "success" scenario is empty`, err.Error())
+}
+
+func (suite *SandboxFixturesTestSuite) TestPayout() {
+	result, err := suite.testable.Payout(TransactionMethodMobileMoney, (*suite.providers.Data)[0], SandboxScenarioSuccess, 0)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), result.isValid())
+	assert.Equal(suite.T(), "mtn_ug", result.ProviderId)
+	assert.Equal(suite.T(), "256777000123", result.AccountNumber)
+	assert.Equal(suite.T(), DefaultSandboxFixturesName, result.AccountName)
+}
+
+func (suite *SandboxFixturesTestSuite) TestBankPayout() {
+	result, err := suite.testable.BankPayout((*suite.banks.Data)[1], "001", SandboxScenarioSuccess, 0)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), result.isValid())
+	assert.Equal(suite.T(), TransactionMethodBank, result.Method)
+	assert.Equal(suite.T(), CurrencyCodeNGN, result.Currency)
+	assert.Equal(suite.T(), "bank_ng", result.ProviderId)
+	assert.Equal(suite.T(), "citi_bank", result.ExtraParams.BankCode)
+	assert.Equal(suite.T(), "001", result.ExtraParams.BankBranchCode)
+	assert.Equal(suite.T(), float64(1000), result.Amount)
+}
+
+func TestSandboxFixturesTestSuite(t *testing.T) {
+	suite.Run(t, new(SandboxFixturesTestSuite))
+}
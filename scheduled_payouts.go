@@ -0,0 +1,366 @@
+package dusupay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+//ScheduledPayoutStatusCode type
+type ScheduledPayoutStatusCode string
+
+//ScheduledPayoutStatusScheduled const
+const ScheduledPayoutStatusScheduled ScheduledPayoutStatusCode = "SCHEDULED"
+
+//ScheduledPayoutStatusSubmitted const
+const ScheduledPayoutStatusSubmitted ScheduledPayoutStatusCode = "SUBMITTED"
+
+//ScheduledPayoutStatusFailed const
+const ScheduledPayoutStatusFailed ScheduledPayoutStatusCode = "FAILED"
+
+//ScheduledPayoutStatusUnknown const (submission outcome is unknown, reconcile by the merchant reference)
+const ScheduledPayoutStatusUnknown ScheduledPayoutStatusCode = "UNKNOWN"
+
+//ScheduledPayoutStatusCancelled const
+const ScheduledPayoutStatusCancelled ScheduledPayoutStatusCode = "CANCELLED"
+
+//PayoutCreatorInterface payouts creation interface (implemented by PayoutsResource)
+type PayoutCreatorInterface interface {
+	Create(ctx context.Context, req *PayoutRequest) (*PayoutResponse, *http.Response, error)
+}
+
+//BalancesFetcherInterface merchant balances interface (implemented by MerchantsResource)
+type BalancesFetcherInterface interface {
+	GetBalances(ctx context.Context) (*BalancesResponse, *http.Response, error)
+}
+
+//ScheduledPayout payout request queued for the execution at the future time
+type ScheduledPayout struct {
+	ID          string                    `json:"id"`
+	Request     *PayoutRequest            `json:"request"`
+	ExecuteAt   time.Time                 `json:"execute_at"`
+	Status      ScheduledPayoutStatusCode `json:"status"`
+	SubmittedAt time.Time                 `json:"submitted_at"`
+	Response    *PayoutResponseData       `json:"response"`
+	LastError   string                    `json:"last_error"`
+}
+
+//IsDue check is scheduled payout should be executed at the moment
+func (sp *ScheduledPayout) IsDue(now time.Time) bool {
+	return sp.Status == ScheduledPayoutStatusScheduled && !sp.ExecuteAt.After(now)
+}
+
+//ScheduledPayoutStorageInterface scheduled payouts storage interface
+type ScheduledPayoutStorageInterface interface {
+	Save(payout *ScheduledPayout) error
+	Get(id string) (*ScheduledPayout, error)
+	GetDue(now time.Time) ([]*ScheduledPayout, error)
+}
+
+//NewScheduledPayoutMemoryStorage create new in-memory scheduled payouts storage
+func NewScheduledPayoutMemoryStorage() *ScheduledPayoutMemoryStorage {
+	return &ScheduledPayoutMemoryStorage{payouts: make(map[string]ScheduledPayout)}
+}
+
+//ScheduledPayoutMemoryStorage in-memory scheduled payouts storage
+type ScheduledPayoutMemoryStorage struct {
+	mu      sync.RWMutex
+	payouts map[string]ScheduledPayout
+}
+
+//Save save scheduled payout
+func (ms *ScheduledPayoutMemoryStorage) Save(payout *ScheduledPayout) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	p := *payout
+	p.Request = copyPayoutRequest(payout.Request)
+	ms.payouts[payout.ID] = p
+	return nil
+}
+
+//Get get scheduled payout by id
+func (ms *ScheduledPayoutMemoryStorage) Get(id string) (*ScheduledPayout, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	payout, ok := ms.payouts[id]
+	if !ok {
+		return nil, fmt.Errorf(`scheduled payout "%s" not found`, id)
+	}
+	payout.Request = copyPayoutRequest(payout.Request)
+	return &payout, nil
+}
+
+//GetDue get scheduled payouts which should be executed at the moment (ordered by execution date)
+func (ms *ScheduledPayoutMemoryStorage) GetDue(now time.Time) ([]*ScheduledPayout, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	var due []*ScheduledPayout
+	for _, payout := range ms.payouts {
+		if payout.IsDue(now) {
+			p := payout
+			p.Request = copyPayoutRequest(payout.Request)
+			due = append(due, &p)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].ExecuteAt.Equal(due[j].ExecuteAt) {
+			return due[i].ID < due[j].ID
+		}
+		return due[i].ExecuteAt.Before(due[j].ExecuteAt)
+	})
+	return due, nil
+}
+
+//BusinessDayConventionCode type
+type BusinessDayConventionCode string
+
+//BusinessDayFollowing const (move non-business day to the next business day)
+const BusinessDayFollowing BusinessDayConventionCode = "FOLLOWING"
+
+//BusinessDayPreceding const (move non-business day to the previous business day)
+const BusinessDayPreceding BusinessDayConventionCode = "PRECEDING"
+
+//NewBusinessCalendar create new business calendar with saturday and sunday weekend
+func NewBusinessCalendar(holidays ...time.Time) *BusinessCalendar {
+	bc := &BusinessCalendar{
+		Weekend:  []time.Weekday{time.Saturday, time.Sunday},
+		holidays: make(map[string]bool),
+	}
+	for _, holiday := range holidays {
+		bc.AddHoliday(holiday)
+	}
+	return bc
+}
+
+//BusinessCalendar business days calendar
+type BusinessCalendar struct {
+	Weekend  []time.Weekday
+	holidays map[string]bool
+}
+
+//AddHoliday add non-business date
+func (bc *BusinessCalendar) AddHoliday(date time.Time) {
+	bc.holidays[date.Format("2006-01-02")] = true
+}
+
+//IsBusinessDay check is date a business day
+func (bc *BusinessCalendar) IsBusinessDay(date time.Time) bool {
+	for _, day := range bc.Weekend {
+		if date.Weekday() == day {
+			return false
+		}
+	}
+	return !bc.holidays[date.Format("2006-01-02")]
+}
+
+//Adjust move non-business date to the business day according to the convention
+func (bc *BusinessCalendar) Adjust(date time.Time, convention BusinessDayConventionCode) time.Time {
+	step := 1
+	if convention == BusinessDayPreceding {
+		step = -1
+	}
+	//a year without business days means a broken calendar, keep the original date
+	for i := 0; i < 366; i++ {
+		if bc.IsBusinessDay(date) {
+			return date
+		}
+		date = date.AddDate(0, 0, step)
+	}
+	return date
+}
+
+//MonthlyDates build monthly execution dates on the day of month (clamped to the month end) adjusted to the business days
+func (bc *BusinessCalendar) MonthlyDates(from time.Time, day int, months int, convention BusinessDayConventionCode) []time.Time {
+	dates := make([]time.Time, 0, months)
+	for i := 0; i < months; i++ {
+		firstDay := time.Date(from.Year(), from.Month()+time.Month(i), 1, from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+		d := day
+		if lastDay := firstDay.AddDate(0, 1, -1).Day(); d > lastDay {
+			d = lastDay
+		}
+		dates = append(dates, bc.Adjust(firstDay.AddDate(0, 0, d-1), convention))
+	}
+	return dates
+}
+
+//ScheduledPayoutHooks scheduled payouts result callbacks (all callbacks are optional)
+type ScheduledPayoutHooks struct {
+	OnSubmitted func(payout *ScheduledPayout, response *PayoutResponse)
+	OnFailed    func(payout *ScheduledPayout, err error)
+	//OnUnknown payout could have been created despite the error (e.g. transport error or timeout)
+	OnUnknown func(payout *ScheduledPayout, err error)
+}
+
+//NewPayoutScheduler create new payouts scheduler (balances fetcher is optional and disables the balance pre-check when nil)
+func NewPayoutScheduler(payouts PayoutCreatorInterface, balances BalancesFetcherInterface, storage ScheduledPayoutStorageInterface, hooks *ScheduledPayoutHooks) *PayoutScheduler {
+	if hooks == nil {
+		hooks = &ScheduledPayoutHooks{}
+	}
+	return &PayoutScheduler{payouts: payouts, balances: balances, storage: storage, hooks: hooks}
+}
+
+//PayoutScheduler future-dated payouts scheduler
+type PayoutScheduler struct {
+	//FeeEstimator optional payout fee estimator, the estimated fee is included in the balance pre-check
+	FeeEstimator FeeEstimatorInterface
+	//FeeMargin balance pre-check margin in percent of the payout amount (used when FeeEstimator is not set, 1.5 means 1.5%)
+	FeeMargin float64
+	mu        sync.Mutex
+	payouts   PayoutCreatorInterface
+	balances  BalancesFetcherInterface
+	storage   ScheduledPayoutStorageInterface
+	hooks     *ScheduledPayoutHooks
+}
+
+//Schedule validate and queue payout request for the execution at the time
+func (ps *PayoutScheduler) Schedule(id string, req *PayoutRequest, executeAt time.Time) (*ScheduledPayout, error) {
+	if id == "" {
+		return nil, fmt.Errorf(`PayoutScheduler.Schedule error: parameter "id" is empty`)
+	}
+	err := req.isValid()
+	if err != nil {
+		return nil, fmt.Errorf("PayoutScheduler.Schedule error: %v", err)
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if _, err := ps.storage.Get(id); err == nil {
+		return nil, fmt.Errorf(`PayoutScheduler.Schedule error: scheduled payout "%s" already exists`, id)
+	}
+	//the scheduled payout keeps its own request copy, so the caller can not change it before the execution
+	payout := &ScheduledPayout{ID: id, Request: copyPayoutRequest(req), ExecuteAt: executeAt, Status: ScheduledPayoutStatusScheduled}
+	err = ps.storage.Save(payout)
+	if err != nil {
+		return nil, fmt.Errorf("PayoutScheduler.Schedule error: %v", err)
+	}
+	return payout, nil
+}
+
+//Cancel cancel scheduled payout before the execution
+func (ps *PayoutScheduler) Cancel(id string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	payout, err := ps.storage.Get(id)
+	if err != nil {
+		return fmt.Errorf("PayoutScheduler.Cancel error: %v", err)
+	}
+	if payout.Status != ScheduledPayoutStatusScheduled {
+		return fmt.Errorf(`PayoutScheduler.Cancel error: scheduled payout "%s" is already "%s"`, id, payout.Status)
+	}
+	payout.Status = ScheduledPayoutStatusCancelled
+	err = ps.storage.Save(payout)
+	if err != nil {
+		return fmt.Errorf("PayoutScheduler.Cancel error: %v", err)
+	}
+	return nil
+}
+
+//Run execute all due payouts, per-payout failures are reported through the hooks
+func (ps *PayoutScheduler) Run(ctx context.Context, now time.Time) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	due, err := ps.storage.GetDue(now)
+	if err != nil {
+		return fmt.Errorf("PayoutScheduler.Run error: %v", err)
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	var balances *BalancesResponseData
+	if ps.balances != nil {
+		rsp, _, err := ps.balances.GetBalances(ctx)
+		if err != nil {
+			return fmt.Errorf("PayoutScheduler.Run error: %v", err)
+		}
+		balances = rsp.Data
+	}
+	//amounts submitted during the run are not reflected in the fetched balances yet
+	spent := make(map[CurrencyCode]float64)
+	for _, payout := range due {
+		var debit float64
+		if balances != nil {
+			debit, err = ps.estimateDebit(ctx, payout.Request)
+			if err == nil {
+				err = balances.CheckSufficientBalance(payout.Request.Currency, spent[payout.Request.Currency]+debit)
+			}
+			if err != nil {
+				err = ps.fail(payout, err)
+				if err != nil {
+					return fmt.Errorf("PayoutScheduler.Run error: %v", err)
+				}
+				continue
+			}
+		}
+		rsp, _, err := ps.payouts.Create(ctx, payout.Request)
+		if err != nil && rsp != nil && isRejectedResponse(&rsp.ResponseBody) {
+			err = ps.fail(payout, err)
+			if err != nil {
+				return fmt.Errorf("PayoutScheduler.Run error: %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			//payout could have been created, the amount is kept as spent until reconciliation
+			spent[payout.Request.Currency] += debit
+			err = ps.unknown(payout, err)
+			if err != nil {
+				return fmt.Errorf("PayoutScheduler.Run error: %v", err)
+			}
+			continue
+		}
+		spent[payout.Request.Currency] += debit
+		payout.Status = ScheduledPayoutStatusSubmitted
+		payout.SubmittedAt = now
+		payout.Response = rsp.Data
+		payout.LastError = ""
+		err = ps.storage.Save(payout)
+		if err != nil {
+			return fmt.Errorf("PayoutScheduler.Run error: %v", err)
+		}
+		if ps.hooks.OnSubmitted != nil {
+			ps.hooks.OnSubmitted(payout, rsp)
+		}
+	}
+	return nil
+}
+
+//estimateDebit estimate total balance debit of the payout (amount with the fee)
+func (ps *PayoutScheduler) estimateDebit(ctx context.Context, req *PayoutRequest) (float64, error) {
+	if ps.FeeEstimator == nil {
+		return roundAmount(req.Amount + req.Amount*ps.FeeMargin/100), nil
+	}
+	estimate, err := ps.FeeEstimator.EstimateFee(ctx, NewFeeEstimateRequestFromPayout(req))
+	if err != nil {
+		return 0, fmt.Errorf("fee estimation: %v", err)
+	}
+	return estimate.Total, nil
+}
+
+//fail mark scheduled payout as failed and notify the hook
+func (ps *PayoutScheduler) unknown(payout *ScheduledPayout, cause error) error {
+	payout.Status = ScheduledPayoutStatusUnknown
+	payout.LastError = cause.Error()
+	err := ps.storage.Save(payout)
+	if err != nil {
+		return err
+	}
+	if ps.hooks.OnUnknown != nil {
+		ps.hooks.OnUnknown(payout, cause)
+	}
+	return nil
+}
+
+func (ps *PayoutScheduler) fail(payout *ScheduledPayout, cause error) error {
+	payout.Status = ScheduledPayoutStatusFailed
+	payout.LastError = cause.Error()
+	err := ps.storage.Save(payout)
+	if err != nil {
+		return err
+	}
+	if ps.hooks.OnFailed != nil {
+		ps.hooks.OnFailed(payout, cause)
+	}
+	return nil
+}
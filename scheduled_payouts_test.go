@@ -0,0 +1,268 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type stubPayoutCreator struct {
+	requests []*PayoutRequest
+	err      error
+	//response returned with the error (API rejection)
+	response *PayoutResponse
+}
+
+func (s *stubPayoutCreator) Create(ctx context.Context, req *PayoutRequest) (*PayoutResponse, *http.Response, error) {
+	s.requests = append(s.requests, req)
+	if s.err != nil {
+		return s.response, nil, s.err
+	}
+	data := &PayoutResponseData{MerchantReference: req.MerchantReference, TransactionStatus: "PENDING"}
+	return &PayoutResponse{ResponseBody: ResponseBody{Code: http.StatusAccepted}, Data: data}, nil, nil
+}
+
+type stubBalancesFetcher struct {
+	balances BalancesResponseData
+	err      error
+	//onGet called during the request
+	onGet func()
+}
+
+func (s *stubBalancesFetcher) GetBalances(ctx context.Context) (*BalancesResponse, *http.Response, error) {
+	if s.onGet != nil {
+		s.onGet()
+	}
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return &BalancesResponse{ResponseBody: ResponseBody{Code: http.StatusOK}, Data: &s.balances}, nil, nil
+}
+
+func buildStubScheduledPayoutRequest(reference string, amount float64) *PayoutRequest {
+	return &PayoutRequest{
+		Currency:          CurrencyCodeUGX,
+		Amount:            amount,
+		Method:            TransactionMethodMobileMoney,
+		ProviderId:        "mtn_ug",
+		AccountNumber:     "256777111786",
+		AccountName:       "John Doe",
+		MerchantReference: reference,
+		Narration:         "Salary",
+	}
+}
+
+type PayoutSchedulerTestSuite struct {
+	suite.Suite
+	now       time.Time
+	payouts   *stubPayoutCreator
+	balances  *stubBalancesFetcher
+	storage   *ScheduledPayoutMemoryStorage
+	submitted []string
+	failed    []string
+	unknown   []string
+	testable  *PayoutScheduler
+}
+
+func (suite *PayoutSchedulerTestSuite) SetupTest() {
+	suite.now = time.Date(2022, 6, 30, 8, 0, 0, 0, time.UTC)
+	suite.payouts = &stubPayoutCreator{}
+	suite.balances = &stubBalancesFetcher{balances: BalancesResponseData{{Currency: "UGX", Balance: 150000}}}
+	suite.storage = NewScheduledPayoutMemoryStorage()
+	suite.submitted = nil
+	suite.failed = nil
+	suite.unknown = nil
+	hooks := &ScheduledPayoutHooks{
+		OnSubmitted: func(payout *ScheduledPayout, response *PayoutResponse) {
+			suite.submitted = append(suite.submitted, payout.ID)
+		},
+		OnFailed: func(payout *ScheduledPayout, err error) {
+			suite.failed = append(suite.failed, payout.ID)
+		},
+		OnUnknown: func(payout *ScheduledPayout, err error) {
+			suite.unknown = append(suite.unknown, payout.ID)
+		},
+	}
+	suite.testable = NewPayoutScheduler(suite.payouts, suite.balances, suite.storage, hooks)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestScheduleInvalidRequest() {
+	result, err := suite.testable.Schedule("p1", &PayoutRequest{}, suite.now)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `PayoutScheduler.Schedule error: parameter "currency" is empty`, err.Error())
+}
+
+func (suite *PayoutSchedulerTestSuite) TestScheduleDuplicate() {
+	_, err := suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100), suite.now)
+	assert.NoError(suite.T(), err)
+	_, err = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100), suite.now)
+	assert.Equal(suite.T(), `PayoutScheduler.Schedule error: scheduled payout "p1" already exists`, err.Error())
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunSubmitsDuePayouts() {
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 50000), suite.now)
+	_, _ = suite.testable.Schedule("p2", buildStubScheduledPayoutRequest("r2", 50000), suite.now.Add(time.Hour))
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+	assert.Equal(suite.T(), []string{"p1"}, suite.submitted)
+	payout, _ := suite.storage.Get("p1")
+	assert.Equal(suite.T(), ScheduledPayoutStatusSubmitted, payout.Status)
+	assert.Equal(suite.T(), suite.now, payout.SubmittedAt)
+	assert.Equal(suite.T(), "r1", payout.Response.MerchantReference)
+
+	//submitted payouts are not executed twice
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now.Add(time.Hour)))
+	assert.Len(suite.T(), suite.payouts.requests, 2)
+	assert.Equal(suite.T(), []string{"p1", "p2"}, suite.submitted)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunBalancePreCheck() {
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100000), suite.now)
+	_, _ = suite.testable.Schedule("p2", buildStubScheduledPayoutRequest("r2", 100000), suite.now)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+	assert.Equal(suite.T(), []string{"p1"}, suite.submitted)
+	assert.Equal(suite.T(), []string{"p2"}, suite.failed)
+	payout, _ := suite.storage.Get("p2")
+	assert.Equal(suite.T(), ScheduledPayoutStatusFailed, payout.Status)
+	assert.Equal(suite.T(), `insufficient "UGX" balance: 150000 < 200000`, payout.LastError)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunBalancePreCheckWithFeeEstimate() {
+	suite.testable.FeeEstimator = &FeeSchedule{Rules: []*FeeRule{{TransactionType: TransactionTypePayout, Fixed: 500}}}
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100000), suite.now)
+	_, _ = suite.testable.Schedule("p2", buildStubScheduledPayoutRequest("r2", 49800), suite.now)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Equal(suite.T(), []string{"p1"}, suite.submitted)
+	payout, _ := suite.storage.Get("p2")
+	assert.Equal(suite.T(), `insufficient "UGX" balance: 150000 < 150800`, payout.LastError)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunBalancePreCheckWithFeeMargin() {
+	suite.testable.FeeMargin = 2
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 150000), suite.now)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+	payout, _ := suite.storage.Get("p1")
+	assert.Equal(suite.T(), `insufficient "UGX" balance: 150000 < 153000`, payout.LastError)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunFeeEstimateError() {
+	suite.testable.FeeEstimator = &FeeSchedule{}
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100), suite.now)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Equal(suite.T(), []string{"p1"}, suite.failed)
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestScheduleKeepsRequestCopy() {
+	req := buildStubScheduledPayoutRequest("r1", 100)
+	_, _ = suite.testable.Schedule("p1", req, suite.now)
+	req.Amount = 1000000
+	req.AccountNumber = "256700000000"
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+	assert.Equal(suite.T(), 100.0, suite.payouts.requests[0].Amount)
+	assert.Equal(suite.T(), "256777111786", suite.payouts.requests[0].AccountNumber)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunBalancesError() {
+	suite.balances.err = errors.New("Unauthorized API access. Unknown Merchant")
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100), suite.now)
+	err := suite.testable.Run(context.Background(), suite.now)
+	assert.Equal(suite.T(), "PayoutScheduler.Run error: Unauthorized API access. Unknown Merchant", err.Error())
+	payout, _ := suite.storage.Get("p1")
+	assert.Equal(suite.T(), ScheduledPayoutStatusScheduled, payout.Status)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunPayoutError() {
+	suite.payouts.err = errors.New("Invalid account number")
+	suite.payouts.response = &PayoutResponse{ResponseBody: ResponseBody{Code: http.StatusBadRequest, Status: "error", Message: "Invalid account number"}}
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100), suite.now)
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Equal(suite.T(), []string{"p1"}, suite.failed)
+	assert.Empty(suite.T(), suite.unknown)
+	payout, _ := suite.storage.Get("p1")
+	assert.Equal(suite.T(), ScheduledPayoutStatusFailed, payout.Status)
+	assert.Equal(suite.T(), "Invalid account number", payout.LastError)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunPayoutTransportError() {
+	suite.payouts.err = errors.New("context deadline exceeded")
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100000), suite.now)
+	_, _ = suite.testable.Schedule("p2", buildStubScheduledPayoutRequest("r2", 100000), suite.now.Add(time.Minute))
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now.Add(time.Hour)))
+	assert.Equal(suite.T(), []string{"p1"}, suite.unknown)
+	payout, _ := suite.storage.Get("p1")
+	assert.Equal(suite.T(), ScheduledPayoutStatusUnknown, payout.Status)
+	assert.Equal(suite.T(), "r1", payout.Request.MerchantReference)
+	assert.Equal(suite.T(), "context deadline exceeded", payout.LastError)
+	assert.False(suite.T(), payout.IsDue(suite.now.Add(time.Hour)))
+	//amount of the unknown payout is kept reserved in the balance pre-check
+	assert.Equal(suite.T(), []string{"p2"}, suite.failed)
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestRunWithoutBalancesFetcher() {
+	testable := NewPayoutScheduler(suite.payouts, nil, suite.storage, nil)
+	_, _ = testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 1000000), suite.now)
+	assert.NoError(suite.T(), testable.Run(context.Background(), suite.now))
+	assert.Len(suite.T(), suite.payouts.requests, 1)
+}
+
+func (suite *PayoutSchedulerTestSuite) TestCancel() {
+	_, _ = suite.testable.Schedule("p1", buildStubScheduledPayoutRequest("r1", 100), suite.now)
+	assert.NoError(suite.T(), suite.testable.Cancel("p1"))
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.now))
+	assert.Len(suite.T(), suite.payouts.requests, 0)
+	err := suite.testable.Cancel("p1")
+	assert.Equal(suite.T(), `PayoutScheduler.Cancel error: scheduled payout "p1" is already "CANCELLED"`, err.Error())
+	err = suite.testable.Cancel("unknown")
+	assert.Equal(suite.T(), `PayoutScheduler.Cancel error: scheduled payout "unknown" not found`, err.Error())
+}
+
+func TestPayoutSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutSchedulerTestSuite))
+}
+
+type BusinessCalendarTestSuite struct {
+	suite.Suite
+}
+
+func (suite *BusinessCalendarTestSuite) TestIsBusinessDay() {
+	calendar := NewBusinessCalendar(time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC))
+	assert.True(suite.T(), calendar.IsBusinessDay(time.Date(2022, 6, 2, 9, 0, 0, 0, time.UTC)))
+	assert.False(suite.T(), calendar.IsBusinessDay(time.Date(2022, 6, 3, 9, 0, 0, 0, time.UTC)))
+	assert.False(suite.T(), calendar.IsBusinessDay(time.Date(2022, 6, 4, 9, 0, 0, 0, time.UTC)))
+}
+
+func (suite *BusinessCalendarTestSuite) TestAdjust() {
+	calendar := NewBusinessCalendar(time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC))
+	saturday := time.Date(2022, 6, 4, 9, 0, 0, 0, time.UTC)
+	assert.Equal(suite.T(), time.Date(2022, 6, 6, 9, 0, 0, 0, time.UTC), calendar.Adjust(saturday, BusinessDayFollowing))
+	assert.Equal(suite.T(), time.Date(2022, 6, 2, 9, 0, 0, 0, time.UTC), calendar.Adjust(saturday, BusinessDayPreceding))
+}
+
+func (suite *BusinessCalendarTestSuite) TestMonthlyDates() {
+	calendar := NewBusinessCalendar()
+	result := calendar.MonthlyDates(time.Date(2022, 4, 1, 8, 0, 0, 0, time.UTC), 31, 3, BusinessDayPreceding)
+	assert.Equal(suite.T(), []time.Time{
+		time.Date(2022, 4, 29, 8, 0, 0, 0, time.UTC),
+		time.Date(2022, 5, 31, 8, 0, 0, 0, time.UTC),
+		time.Date(2022, 6, 30, 8, 0, 0, 0, time.UTC),
+	}, result)
+}
+
+func TestBusinessCalendarTestSuite(t *testing.T) {
+	suite.Run(t, new(BusinessCalendarTestSuite))
+}
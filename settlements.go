@@ -0,0 +1,165 @@
+package dusupay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//SettlementRequest withdrawal of the currency balance to the registered merchant bank account
+type SettlementRequest struct {
+	Currency          CurrencyCode `json:"currency"`
+	Amount            float64      `json:"amount"`
+	BankAccountId     string       `json:"bank_account_id"`
+	MerchantReference string       `json:"merchant_reference"`
+	Narration         string       `json:"narration"`
+}
+
+//Check is valid SettlementRequest parameters
+func (sr *SettlementRequest) isValid() error {
+	var err error
+	if sr.Currency == "" {
+		err = fmt.Errorf(`parameter "currency" is empty`)
+	} else if sr.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	} else if sr.Amount < 0 {
+		err = fmt.Errorf(`parameter "amount" is negative`)
+	} else if sr.BankAccountId == "" {
+		err = fmt.Errorf(`parameter "bank_account_id" is empty`)
+	} else if sr.MerchantReference == "" {
+		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
+	} else if sr.Narration == "" {
+		err = fmt.Errorf(`parameter "narration" is empty`)
+	}
+	return err
+}
+
+//SettlementsFilter settlements list filter (all parameters are optional)
+type SettlementsFilter struct {
+	Currency CurrencyCode          `json:"currency,omitempty"`
+	Status   TransactionStatusCode `json:"transaction_status,omitempty"`
+	Page     int                   `json:"page,omitempty"`
+}
+
+//SettlementResponse struct
+type SettlementResponse struct {
+	ResponseBody
+	Data *SettlementResponseData `json:"data,omitempty"`
+}
+
+//SettlementResponseData struct
+type SettlementResponseData struct {
+	ID                int64   `json:"id"`
+	RequestAmount     float64 `json:"request_amount"`
+	RequestCurrency   string  `json:"request_currency"`
+	TransactionFee    float64 `json:"transaction_fee"`
+	TotalDebit        float64 `json:"total_debit"`
+	BankAccountID     string  `json:"bank_account_id"`
+	MerchantReference string  `json:"merchant_reference"`
+	InternalReference string  `json:"internal_reference"`
+	TransactionStatus string  `json:"transaction_status"`
+	TransactionType   string  `json:"transaction_type"`
+	Message           string  `json:"message"`
+	CreatedAt         string  `json:"created_at"`
+}
+
+//SettlementsResponse struct
+type SettlementsResponse struct {
+	ResponseBody
+	Data *SettlementsResponseData `json:"data,omitempty"`
+}
+
+//SettlementsResponseData struct
+type SettlementsResponseData []*SettlementResponseData
+
+//UnmarshalJSON unmarshal json data
+func (rsp *SettlementsResponseData) UnmarshalJSON(data []byte) error {
+	if isEmptyObjectResponseData(data) {
+		return nil
+	}
+	var arr []*SettlementResponseData
+	err := json.Unmarshal(data, &arr)
+	if err != nil {
+		return err
+	}
+	*rsp = append(*rsp, arr...)
+	return nil
+}
+
+//SettlementWebhook struct
+type SettlementWebhook struct {
+	ID                int64   `json:"id"`
+	RequestAmount     float64 `json:"request_amount"`
+	RequestCurrency   string  `json:"request_currency"`
+	TransactionFee    float64 `json:"transaction_fee"`
+	TotalDebit        float64 `json:"total_debit"`
+	BankAccountID     string  `json:"bank_account_id"`
+	MerchantReference string  `json:"merchant_reference"`
+	InternalReference string  `json:"internal_reference"`
+	TransactionStatus string  `json:"transaction_status"`
+	TransactionType   string  `json:"transaction_type"`
+	Message           string  `json:"message"`
+	AccountNumber     string  `json:"account_number"`
+	AccountName       string  `json:"account_name"`
+	InstitutionName   string  `json:"institution_name"`
+}
+
+func (sw *SettlementWebhook) BuildPayloadString(url string) string {
+	return fmt.Sprintf("%d:%s:%s:%s", sw.ID, sw.InternalReference, sw.TransactionStatus, url)
+}
+
+//SettlementsResource wrapper
+type SettlementsResource struct {
+	ResourceAbstract
+}
+
+//Create settlement request (see https://docs.dusupay.com/appendix/settlements)
+func (r *SettlementsResource) Create(ctx context.Context, req *SettlementRequest) (*SettlementResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("SettlementsResource.Create error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SettlementsResource.Create error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/settlements", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SettlementsResource.Create error: %v", err)
+	}
+	var result SettlementResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("SettlementsResource.Create error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//GetList get past settlements list (see https://docs.dusupay.com/appendix/settlements)
+func (r *SettlementsResource) GetList(ctx context.Context, filter *SettlementsFilter) (*SettlementsResponse, *http.Response, error) {
+	if filter == nil {
+		filter = &SettlementsFilter{}
+	}
+	query, err := transformStructToMap(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SettlementsResource.GetList error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Get(ctx, "v1/settlements", query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SettlementsResource.GetList error: %v", err)
+	}
+	var result SettlementsResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("SettlementsResource.GetList error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
@@ -0,0 +1,206 @@
+package dusupay
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type SettlementsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SettlementsTestSuite) TestSettlementRequestIsValid() {
+	cases := map[string]SettlementRequest{
+		`parameter "currency" is empty`:           {Amount: 10, BankAccountId: "id", MerchantReference: "ref", Narration: "narration"},
+		`parameter "amount" is empty`:             {Currency: CurrencyCodeUGX, BankAccountId: "id", MerchantReference: "ref", Narration: "narration"},
+		`parameter "amount" is negative`:          {Currency: CurrencyCodeUGX, Amount: -10, BankAccountId: "id", MerchantReference: "ref", Narration: "narration"},
+		`parameter "bank_account_id" is empty`:    {Currency: CurrencyCodeUGX, Amount: 10, MerchantReference: "ref", Narration: "narration"},
+		`parameter "merchant_reference" is empty`: {Currency: CurrencyCodeUGX, Amount: 10, BankAccountId: "id", Narration: "narration"},
+		`parameter "narration" is empty`:          {Currency: CurrencyCodeUGX, Amount: 10, BankAccountId: "id", MerchantReference: "ref"},
+	}
+	for message, request := range cases {
+		result := request.isValid()
+		assert.Error(suite.T(), result)
+		assert.Equal(suite.T(), message, result.Error())
+	}
+	request := SettlementRequest{Currency: CurrencyCodeUGX, Amount: 10, BankAccountId: "id", MerchantReference: "ref", Narration: "narration"}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *SettlementsTestSuite) TestSettlementWebhookUnmarshalSuccess() {
+	var webhook SettlementWebhook
+	body, _ := LoadStubResponseData("stubs/webhooks/request/settlement-success.json")
+	err := json.Unmarshal(body, &webhook)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(226), webhook.ID)
+	assert.Equal(suite.T(), float64(500000), webhook.RequestAmount)
+	assert.Equal(suite.T(), "UGX", webhook.RequestCurrency)
+	assert.Equal(suite.T(), float64(5000), webhook.TransactionFee)
+	assert.Equal(suite.T(), float64(505000), webhook.TotalDebit)
+	assert.Equal(suite.T(), "BANK-ACC-01", webhook.BankAccountID)
+	assert.Equal(suite.T(), "settlement-1001", webhook.MerchantReference)
+	assert.Equal(suite.T(), "DUSUPAY405GZM1G5JXGA71IK", webhook.InternalReference)
+	assert.Equal(suite.T(), "COMPLETED", webhook.TransactionStatus)
+	assert.Equal(suite.T(), "settlement", webhook.TransactionType)
+	assert.Equal(suite.T(), "Settlement Completed Successfully", webhook.Message)
+	assert.Equal(suite.T(), "1234567890", webhook.AccountNumber)
+	assert.Equal(suite.T(), "Foo Bar Ltd", webhook.AccountName)
+	assert.Equal(suite.T(), "Stanbic Bank", webhook.InstitutionName)
+}
+
+func (suite *SettlementsTestSuite) TestSettlementWebhookBuildPayloadString() {
+	var webhook SettlementWebhook
+	body, _ := LoadStubResponseData("stubs/webhooks/request/settlement-success.json")
+	_ = json.Unmarshal(body, &webhook)
+	result := webhook.BuildPayloadString("https://www.sample-url.com/callback")
+	assert.Equal(suite.T(), "226:DUSUPAY405GZM1G5JXGA71IK:COMPLETED:https://www.sample-url.com/callback", result)
+}
+
+func TestSettlementsTestSuite(t *testing.T) {
+	suite.Run(t, new(SettlementsTestSuite))
+}
+
+type SettlementsResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *SettlementsResource
+}
+
+func (suite *SettlementsResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &SettlementsResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *SettlementsResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *SettlementsResourceTestSuite) buildRequest() *SettlementRequest {
+	return &SettlementRequest{Currency: CurrencyCodeUGX, Amount: 500000, BankAccountId: "BANK-ACC-01", MerchantReference: "settlement-1001", Narration: "narration"}
+}
+
+func (suite *SettlementsResourceTestSuite) TestCreateSuccess() {
+	body, _ := LoadStubResponseData("stubs/settlements/create/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/settlements", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Create(suite.ctx, suite.buildRequest())
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), http.StatusAccepted, result.Code)
+	assert.Equal(suite.T(), "accepted", result.Status)
+	assert.Equal(suite.T(), "Settlement Initiated", result.Message)
+	assert.Equal(suite.T(), int64(3051), result.Data.ID)
+	assert.Equal(suite.T(), float64(500000), result.Data.RequestAmount)
+	assert.Equal(suite.T(), "UGX", result.Data.RequestCurrency)
+	assert.Equal(suite.T(), float64(5000), result.Data.TransactionFee)
+	assert.Equal(suite.T(), float64(505000), result.Data.TotalDebit)
+	assert.Equal(suite.T(), "BANK-ACC-01", result.Data.BankAccountID)
+	assert.Equal(suite.T(), "settlement-1001", result.Data.MerchantReference)
+	assert.Equal(suite.T(), "DUSUPAY405GZMSETTLE0001", result.Data.InternalReference)
+	assert.Equal(suite.T(), "PENDING", result.Data.TransactionStatus)
+	assert.Equal(suite.T(), "settlement", result.Data.TransactionType)
+	assert.Equal(suite.T(), "2022-06-01 10:00:00", result.Data.CreatedAt)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *SettlementsResourceTestSuite) TestCreateJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/settlements", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Create(suite.ctx, suite.buildRequest())
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *SettlementsResourceTestSuite) TestCreateNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/settlements", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.Create(suite.ctx, suite.buildRequest())
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *SettlementsResourceTestSuite) TestCreateInvalidRequest() {
+	result, rsp, err := suite.testable.Create(suite.ctx, &SettlementRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SettlementsResourceTestSuite) TestGetListSuccess() {
+	body, _ := LoadStubResponseData("stubs/settlements/list/success.json")
+	var query map[string][]string
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/settlements", func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query()
+		return httpmock.NewBytesResponse(http.StatusOK, body), nil
+	})
+
+	result, resp, err := suite.testable.GetList(suite.ctx, &SettlementsFilter{Currency: CurrencyCodeUGX})
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Equal(suite.T(), []string{"UGX"}, query["currency"])
+	assert.NotContains(suite.T(), query, "page")
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Len(suite.T(), *result.Data, 2)
+	assert.Equal(suite.T(), int64(3051), (*result.Data)[0].ID)
+	assert.Equal(suite.T(), "COMPLETED", (*result.Data)[0].TransactionStatus)
+	assert.Equal(suite.T(), int64(3052), (*result.Data)[1].ID)
+	assert.Equal(suite.T(), "USD", (*result.Data)[1].RequestCurrency)
+}
+
+func (suite *SettlementsResourceTestSuite) TestGetListWithoutFilter() {
+	body, _ := LoadStubResponseData("stubs/settlements/list/success.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/settlements", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, _, err := suite.testable.GetList(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), *result.Data, 2)
+}
+
+func (suite *SettlementsResourceTestSuite) TestGetListJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/settlements", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetList(suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *SettlementsResourceTestSuite) TestGetListNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodGet, suite.cfg.Uri+"/v1/settlements", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.GetList(suite.ctx, nil)
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func TestSettlementsResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(SettlementsResourceTestSuite))
+}
@@ -17,6 +17,11 @@ type IncomingWebhookInterface interface {
 	BuildPayloadString(url string) string
 }
 
+//SignatureValidatorInterface interface (implemented by SignatureValidator and MultiKeySignatureValidator)
+type SignatureValidatorInterface interface {
+	ValidateSignature(webhook IncomingWebhookInterface, webhookUrl string, signature string) error
+}
+
 //NewSignatureValidator method
 func NewSignatureValidator(publicKeyBytes []byte) (*SignatureValidator, error) {
 	block, _ := pem.Decode(publicKeyBytes)
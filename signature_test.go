@@ -53,6 +53,14 @@ func (suite *SignatureTestSuite) TestValidateSignatureRefundWebhookSuccess() {
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *SignatureTestSuite) TestValidateSignatureSettlementWebhookSuccess() {
+	rawBytes, _ := ioutil.ReadFile("stubs/rsa/public-key.pem")
+	webhook := &SettlementWebhook{ID: 226, InternalReference: "DUSUPAY405GZM1G5JXGA71IK", TransactionStatus: "COMPLETED"}
+	validator, _ := NewSignatureValidator(rawBytes)
+	err := validator.ValidateSignature(webhook, "https://www.sample-url.com/callback", stubSignature)
+	assert.NoError(suite.T(), err)
+}
+
 func (suite *SignatureTestSuite) TestParsePublicKeyError() {
 	pk, err := parsePublicKey([]byte(`foo`))
 	assert.Error(suite.T(), err)
@@ -0,0 +1,539 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+//SubscriptionIntervalCode type
+type SubscriptionIntervalCode string
+
+//SubscriptionIntervalDaily const
+const SubscriptionIntervalDaily SubscriptionIntervalCode = "DAILY"
+
+//SubscriptionIntervalWeekly const
+const SubscriptionIntervalWeekly SubscriptionIntervalCode = "WEEKLY"
+
+//SubscriptionIntervalMonthly const
+const SubscriptionIntervalMonthly SubscriptionIntervalCode = "MONTHLY"
+
+//SubscriptionStatusCode type
+type SubscriptionStatusCode string
+
+//SubscriptionStatusActive const
+const SubscriptionStatusActive SubscriptionStatusCode = "ACTIVE"
+
+//SubscriptionStatusPastDue const (charge failed, dunning retries are scheduled)
+const SubscriptionStatusPastDue SubscriptionStatusCode = "PAST_DUE"
+
+//SubscriptionStatusUnpaid const (all dunning retries are failed)
+const SubscriptionStatusUnpaid SubscriptionStatusCode = "UNPAID"
+
+//SubscriptionStatusCancelled const
+const SubscriptionStatusCancelled SubscriptionStatusCode = "CANCELLED"
+
+//DefaultSubscriptionRetryDelay delay between the dunning retries
+const DefaultSubscriptionRetryDelay = 24 * time.Hour
+
+//DefaultSubscriptionPendingTimeout time to wait for the pending collection webhook before the charge attempt is failed
+const DefaultSubscriptionPendingTimeout = 24 * time.Hour
+
+//CollectionCreatorInterface collections creation interface (implemented by CollectionsResource)
+type CollectionCreatorInterface interface {
+	Create(ctx context.Context, req *CollectionRequest) (*CollectionResponse, *http.Response, error)
+}
+
+//SubscriptionPlan recurring collection plan
+type SubscriptionPlan struct {
+	ID            string                   `json:"id"`
+	Name          string                   `json:"name"`
+	Currency      CurrencyCode             `json:"currency"`
+	Amount        float64                  `json:"amount"`
+	Method        TransactionMethodCode    `json:"method"`
+	Narration     string                   `json:"narration"`
+	Interval      SubscriptionIntervalCode `json:"interval"`
+	IntervalCount int                      `json:"interval_count"`
+	MaxRetries    int                      `json:"max_retries"`
+	RetryDelay    time.Duration            `json:"retry_delay"`
+}
+
+//Check is valid SubscriptionPlan parameters
+func (sp *SubscriptionPlan) isValid() error {
+	var err error
+	if sp.ID == "" {
+		err = fmt.Errorf(`parameter "id" is empty`)
+	} else if sp.Currency == "" {
+		err = fmt.Errorf(`parameter "currency" is empty`)
+	} else if sp.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	} else if sp.Amount < 0 {
+		err = fmt.Errorf(`parameter "amount" is negative`)
+	} else if sp.Method == "" {
+		err = fmt.Errorf(`parameter "method" is empty`)
+	} else if !isValidTransactionMethod(sp.Method) {
+		err = fmt.Errorf(`parameter "method" is invalid`)
+	} else if sp.Narration == "" {
+		err = fmt.Errorf(`parameter "narration" is empty`)
+	} else if sp.Interval != SubscriptionIntervalDaily && sp.Interval != SubscriptionIntervalWeekly && sp.Interval != SubscriptionIntervalMonthly {
+		err = fmt.Errorf(`parameter "interval" is invalid`)
+	} else if sp.IntervalCount < 0 {
+		err = fmt.Errorf(`parameter "interval_count" is negative`)
+	} else if sp.MaxRetries < 0 {
+		err = fmt.Errorf(`parameter "max_retries" is negative`)
+	}
+	return err
+}
+
+//BillingAt calculate billing date of the subscription cycle (monthly billing day is clamped to the month end)
+func (sp *SubscriptionPlan) BillingAt(startAt time.Time, cycle int) time.Time {
+	count := sp.IntervalCount
+	if count == 0 {
+		count = 1
+	}
+	periods := (cycle - 1) * count
+	switch sp.Interval {
+	case SubscriptionIntervalDaily:
+		return startAt.AddDate(0, 0, periods)
+	case SubscriptionIntervalWeekly:
+		return startAt.AddDate(0, 0, 7*periods)
+	default:
+		firstDay := time.Date(startAt.Year(), startAt.Month()+time.Month(periods), 1, startAt.Hour(), startAt.Minute(), startAt.Second(), startAt.Nanosecond(), startAt.Location())
+		lastDay := firstDay.AddDate(0, 1, -1).Day()
+		day := startAt.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return firstDay.AddDate(0, 0, day-1)
+	}
+}
+
+//GetRetryDelay get delay between the dunning retries
+func (sp *SubscriptionPlan) GetRetryDelay() time.Duration {
+	if sp.RetryDelay <= 0 {
+		return DefaultSubscriptionRetryDelay
+	}
+	return sp.RetryDelay
+}
+
+//SubscriptionCustomer subscription payer account
+type SubscriptionCustomer struct {
+	ID            string `json:"id"`
+	AccountNumber string `json:"account_number"`
+	ProviderId    string `json:"provider_id"`
+	RedirectUrl   string `json:"redirect_url"`
+}
+
+//Check is valid SubscriptionCustomer parameters
+func (sc *SubscriptionCustomer) isValid() error {
+	var err error
+	if sc.ID == "" {
+		err = fmt.Errorf(`parameter "id" is empty`)
+	} else if sc.AccountNumber == "" {
+		err = fmt.Errorf(`parameter "account_number" is empty`)
+	} else if sc.ProviderId == "" {
+		err = fmt.Errorf(`parameter "provider_id" is empty`)
+	}
+	return err
+}
+
+//Subscription customer subscription to the plan
+type Subscription struct {
+	ID            string                 `json:"id"`
+	PlanID        string                 `json:"plan_id"`
+	CustomerID    string                 `json:"customer_id"`
+	Status        SubscriptionStatusCode `json:"status"`
+	Cycle         int                    `json:"cycle"`
+	Attempt       int                    `json:"attempt"`
+	StartAt       time.Time              `json:"start_at"`
+	NextBillingAt time.Time              `json:"next_billing_at"`
+	//merchant reference of the initiated collection which is waiting for the webhook
+	PendingReference string `json:"pending_reference"`
+	//time when the pending collection is considered failed if the webhook is not received
+	PendingExpireAt time.Time `json:"pending_expire_at"`
+	LastError       string    `json:"last_error"`
+}
+
+//IsDue check is subscription should be charged (or its pending collection is expired) at the moment
+func (s *Subscription) IsDue(now time.Time) bool {
+	if s.Status != SubscriptionStatusActive && s.Status != SubscriptionStatusPastDue {
+		return false
+	}
+	if s.PendingReference != "" {
+		return s.IsPendingExpired(now)
+	}
+	return !s.NextBillingAt.After(now)
+}
+
+//IsPendingExpired check is pending collection webhook is not received in time
+func (s *Subscription) IsPendingExpired(now time.Time) bool {
+	return s.PendingReference != "" && !s.PendingExpireAt.IsZero() && !s.PendingExpireAt.After(now)
+}
+
+//MerchantReference build deterministic collection merchant reference for the current cycle attempt
+func (s *Subscription) MerchantReference() string {
+	return BuildSubscriptionMerchantReference(s.ID, s.Cycle, s.Attempt)
+}
+
+//BuildSubscriptionMerchantReference build deterministic subscription collection merchant reference
+func BuildSubscriptionMerchantReference(subscriptionId string, cycle int, attempt int) string {
+	return fmt.Sprintf("sub-%s-%04d-%d", subscriptionId, cycle, attempt)
+}
+
+//SubscriptionStorageInterface subscriptions storage interface
+type SubscriptionStorageInterface interface {
+	SavePlan(plan *SubscriptionPlan) error
+	GetPlan(id string) (*SubscriptionPlan, error)
+	SaveCustomer(customer *SubscriptionCustomer) error
+	GetCustomer(id string) (*SubscriptionCustomer, error)
+	SaveSubscription(subscription *Subscription) error
+	GetSubscription(id string) (*Subscription, error)
+	FindSubscriptionByReference(merchantReference string) (*Subscription, error)
+	GetDueSubscriptions(now time.Time) ([]*Subscription, error)
+}
+
+//NewSubscriptionMemoryStorage create new in-memory subscriptions storage
+func NewSubscriptionMemoryStorage() *SubscriptionMemoryStorage {
+	return &SubscriptionMemoryStorage{
+		plans:         make(map[string]SubscriptionPlan),
+		customers:     make(map[string]SubscriptionCustomer),
+		subscriptions: make(map[string]Subscription),
+	}
+}
+
+//SubscriptionMemoryStorage in-memory subscriptions storage
+type SubscriptionMemoryStorage struct {
+	mu            sync.RWMutex
+	plans         map[string]SubscriptionPlan
+	customers     map[string]SubscriptionCustomer
+	subscriptions map[string]Subscription
+}
+
+//SavePlan save subscription plan
+func (ms *SubscriptionMemoryStorage) SavePlan(plan *SubscriptionPlan) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.plans[plan.ID] = *plan
+	return nil
+}
+
+//GetPlan get subscription plan by id
+func (ms *SubscriptionMemoryStorage) GetPlan(id string) (*SubscriptionPlan, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	plan, ok := ms.plans[id]
+	if !ok {
+		return nil, fmt.Errorf(`subscription plan "%s" not found`, id)
+	}
+	return &plan, nil
+}
+
+//SaveCustomer save subscription customer
+func (ms *SubscriptionMemoryStorage) SaveCustomer(customer *SubscriptionCustomer) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.customers[customer.ID] = *customer
+	return nil
+}
+
+//GetCustomer get subscription customer by id
+func (ms *SubscriptionMemoryStorage) GetCustomer(id string) (*SubscriptionCustomer, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	customer, ok := ms.customers[id]
+	if !ok {
+		return nil, fmt.Errorf(`subscription customer "%s" not found`, id)
+	}
+	return &customer, nil
+}
+
+//SaveSubscription save subscription
+func (ms *SubscriptionMemoryStorage) SaveSubscription(subscription *Subscription) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.subscriptions[subscription.ID] = *subscription
+	return nil
+}
+
+//GetSubscription get subscription by id
+func (ms *SubscriptionMemoryStorage) GetSubscription(id string) (*Subscription, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	subscription, ok := ms.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf(`subscription "%s" not found`, id)
+	}
+	return &subscription, nil
+}
+
+//FindSubscriptionByReference get subscription by the pending collection merchant reference
+func (ms *SubscriptionMemoryStorage) FindSubscriptionByReference(merchantReference string) (*Subscription, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for _, subscription := range ms.subscriptions {
+		if subscription.PendingReference != "" && subscription.PendingReference == merchantReference {
+			s := subscription
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf(`subscription with reference "%s" not found`, merchantReference)
+}
+
+//GetDueSubscriptions get subscriptions which should be charged at the moment (ordered by billing date)
+func (ms *SubscriptionMemoryStorage) GetDueSubscriptions(now time.Time) ([]*Subscription, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	var due []*Subscription
+	for _, subscription := range ms.subscriptions {
+		if subscription.IsDue(now) {
+			s := subscription
+			due = append(due, &s)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].NextBillingAt.Equal(due[j].NextBillingAt) {
+			return due[i].ID < due[j].ID
+		}
+		return due[i].NextBillingAt.Before(due[j].NextBillingAt)
+	})
+	return due, nil
+}
+
+//SubscriptionHooks subscription state changes hooks (all hooks are optional)
+type SubscriptionHooks struct {
+	//collection is initiated for the subscription cycle
+	OnChargeInitiated func(subscription *Subscription, response *CollectionResponse)
+	//subscription cycle is paid
+	OnChargeCompleted func(subscription *Subscription, webhook *CollectionWebhook)
+	//subscription cycle charge attempt is failed
+	OnChargeFailed func(subscription *Subscription, err error)
+	//subscription status is changed
+	OnStatusChanged func(subscription *Subscription, from SubscriptionStatusCode, to SubscriptionStatusCode)
+}
+
+//NewSubscriptionScheduler create new subscriptions scheduler
+func NewSubscriptionScheduler(collections CollectionCreatorInterface, storage SubscriptionStorageInterface, hooks *SubscriptionHooks) *SubscriptionScheduler {
+	if hooks == nil {
+		hooks = &SubscriptionHooks{}
+	}
+	return &SubscriptionScheduler{collections: collections, storage: storage, hooks: hooks}
+}
+
+//SubscriptionScheduler recurring collections scheduler
+type SubscriptionScheduler struct {
+	//PendingTimeout time to wait for the collection webhook (DefaultSubscriptionPendingTimeout when not set)
+	PendingTimeout time.Duration
+	mu             sync.Mutex
+	collections    CollectionCreatorInterface
+	storage        SubscriptionStorageInterface
+	hooks          *SubscriptionHooks
+}
+
+//GetPendingTimeout get time to wait for the collection webhook
+func (ss *SubscriptionScheduler) GetPendingTimeout() time.Duration {
+	if ss.PendingTimeout <= 0 {
+		return DefaultSubscriptionPendingTimeout
+	}
+	return ss.PendingTimeout
+}
+
+//CreatePlan validate and save subscription plan
+func (ss *SubscriptionScheduler) CreatePlan(plan *SubscriptionPlan) error {
+	err := plan.isValid()
+	if err != nil {
+		return fmt.Errorf("SubscriptionScheduler.CreatePlan error: %v", err)
+	}
+	return ss.storage.SavePlan(plan)
+}
+
+//CreateCustomer validate and save subscription customer
+func (ss *SubscriptionScheduler) CreateCustomer(customer *SubscriptionCustomer) error {
+	err := customer.isValid()
+	if err != nil {
+		return fmt.Errorf("SubscriptionScheduler.CreateCustomer error: %v", err)
+	}
+	return ss.storage.SaveCustomer(customer)
+}
+
+//Subscribe subscribe customer to the plan starting from the first billing date
+func (ss *SubscriptionScheduler) Subscribe(id string, planId string, customerId string, startAt time.Time) (*Subscription, error) {
+	if id == "" {
+		return nil, fmt.Errorf(`SubscriptionScheduler.Subscribe error: parameter "id" is empty`)
+	}
+	if _, err := ss.storage.GetPlan(planId); err != nil {
+		return nil, fmt.Errorf("SubscriptionScheduler.Subscribe error: %v", err)
+	}
+	if _, err := ss.storage.GetCustomer(customerId); err != nil {
+		return nil, fmt.Errorf("SubscriptionScheduler.Subscribe error: %v", err)
+	}
+	subscription := &Subscription{
+		ID:            id,
+		PlanID:        planId,
+		CustomerID:    customerId,
+		Status:        SubscriptionStatusActive,
+		Cycle:         1,
+		StartAt:       startAt,
+		NextBillingAt: startAt,
+	}
+	err := ss.storage.SaveSubscription(subscription)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionScheduler.Subscribe error: %v", err)
+	}
+	return subscription, nil
+}
+
+//Cancel cancel subscription (the pending collection webhook is still accepted)
+func (ss *SubscriptionScheduler) Cancel(id string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	subscription, err := ss.storage.GetSubscription(id)
+	if err != nil {
+		return fmt.Errorf("SubscriptionScheduler.Cancel error: %v", err)
+	}
+	return ss.changeStatus(subscription, SubscriptionStatusCancelled)
+}
+
+//Run charge all due subscriptions and fail expired pending charges, per-subscription charge failures are reported through the hooks
+func (ss *SubscriptionScheduler) Run(ctx context.Context, now time.Time) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	due, err := ss.storage.GetDueSubscriptions(now)
+	if err != nil {
+		return fmt.Errorf("SubscriptionScheduler.Run error: %v", err)
+	}
+	for _, subscription := range due {
+		if subscription.IsPendingExpired(now) {
+			//the late webhook of the expired reference is not applied anymore, reconcile it by the merchant reference
+			cause := fmt.Errorf(`pending collection "%s" is expired`, subscription.PendingReference)
+			subscription.PendingReference = ""
+			subscription.PendingExpireAt = time.Time{}
+			err = ss.fail(subscription, cause, now)
+		} else {
+			err = ss.charge(ctx, subscription, now)
+		}
+		if err != nil {
+			return fmt.Errorf("SubscriptionScheduler.Run error: %v", err)
+		}
+	}
+	return nil
+}
+
+//HandleCollectionWebhook apply collection webhook result to the subscription
+func (ss *SubscriptionScheduler) HandleCollectionWebhook(webhook *CollectionWebhook, now time.Time) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	subscription, err := ss.storage.FindSubscriptionByReference(webhook.MerchantReference)
+	if err != nil {
+		return fmt.Errorf("SubscriptionScheduler.HandleCollectionWebhook error: %v", err)
+	}
+	switch TransactionStatusCode(webhook.TransactionStatus) {
+	case TransactionStatusCompleted:
+		plan, err := ss.storage.GetPlan(subscription.PlanID)
+		if err != nil {
+			return fmt.Errorf("SubscriptionScheduler.HandleCollectionWebhook error: %v", err)
+		}
+		subscription.PendingReference = ""
+		subscription.PendingExpireAt = time.Time{}
+		subscription.LastError = ""
+		subscription.Cycle++
+		subscription.Attempt = 0
+		subscription.NextBillingAt = plan.BillingAt(subscription.StartAt, subscription.Cycle)
+		if ss.hooks.OnChargeCompleted != nil {
+			ss.hooks.OnChargeCompleted(subscription, webhook)
+		}
+		if subscription.Status == SubscriptionStatusPastDue {
+			return ss.changeStatus(subscription, SubscriptionStatusActive)
+		}
+		return ss.storage.SaveSubscription(subscription)
+	case TransactionStatusFailed, TransactionStatusCancelled:
+		subscription.PendingReference = ""
+		subscription.PendingExpireAt = time.Time{}
+		return ss.fail(subscription, errors.New(webhook.Message), now)
+	}
+	return nil
+}
+
+//charge initiate subscription cycle collection
+func (ss *SubscriptionScheduler) charge(ctx context.Context, subscription *Subscription, now time.Time) error {
+	plan, err := ss.storage.GetPlan(subscription.PlanID)
+	if err != nil {
+		return err
+	}
+	customer, err := ss.storage.GetCustomer(subscription.CustomerID)
+	if err != nil {
+		return err
+	}
+	req := &CollectionRequest{
+		Currency:          plan.Currency,
+		Amount:            plan.Amount,
+		Method:            plan.Method,
+		ProviderId:        customer.ProviderId,
+		AccountNumber:     customer.AccountNumber,
+		MerchantReference: subscription.MerchantReference(),
+		Narration:         plan.Narration,
+		RedirectUrl:       customer.RedirectUrl,
+	}
+	//the request is rejected locally, so the collection is not created for sure
+	err = req.isValid()
+	if err != nil {
+		return ss.fail(subscription, err, now)
+	}
+	rsp, _, err := ss.collections.Create(ctx, req)
+	if err != nil && rsp != nil && isRejectedResponse(&rsp.ResponseBody) {
+		return ss.fail(subscription, err, now)
+	}
+	//on the ambiguous errors (transport, unparsed response) the collection may be created, so the same reference is kept pending until the webhook
+	subscription.PendingReference = req.MerchantReference
+	subscription.PendingExpireAt = now.Add(ss.GetPendingTimeout())
+	subscription.LastError = ""
+	if err != nil {
+		subscription.LastError = err.Error()
+		return ss.storage.SaveSubscription(subscription)
+	}
+	if ss.hooks.OnChargeInitiated != nil {
+		ss.hooks.OnChargeInitiated(subscription, rsp)
+	}
+	return ss.storage.SaveSubscription(subscription)
+}
+
+//fail register failed charge attempt and schedule the dunning retry
+func (ss *SubscriptionScheduler) fail(subscription *Subscription, cause error, now time.Time) error {
+	plan, err := ss.storage.GetPlan(subscription.PlanID)
+	if err != nil {
+		return err
+	}
+	subscription.LastError = cause.Error()
+	if ss.hooks.OnChargeFailed != nil {
+		ss.hooks.OnChargeFailed(subscription, cause)
+	}
+	if subscription.Status == SubscriptionStatusCancelled {
+		return ss.storage.SaveSubscription(subscription)
+	}
+	if subscription.Attempt >= plan.MaxRetries {
+		return ss.changeStatus(subscription, SubscriptionStatusUnpaid)
+	}
+	subscription.Attempt++
+	subscription.NextBillingAt = now.Add(plan.GetRetryDelay())
+	if subscription.Status != SubscriptionStatusPastDue {
+		return ss.changeStatus(subscription, SubscriptionStatusPastDue)
+	}
+	return ss.storage.SaveSubscription(subscription)
+}
+
+//changeStatus save subscription with the new status and notify the hook
+func (ss *SubscriptionScheduler) changeStatus(subscription *Subscription, status SubscriptionStatusCode) error {
+	from := subscription.Status
+	subscription.Status = status
+	err := ss.storage.SaveSubscription(subscription)
+	if err != nil {
+		return err
+	}
+	if from != status && ss.hooks.OnStatusChanged != nil {
+		ss.hooks.OnStatusChanged(subscription, from, status)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type stubCollectionCreator struct {
+	requests []*CollectionRequest
+	response *CollectionResponse
+	err      error
+}
+
+func (s *stubCollectionCreator) Create(ctx context.Context, req *CollectionRequest) (*CollectionResponse, *http.Response, error) {
+	s.requests = append(s.requests, req)
+	if s.err != nil {
+		return s.response, nil, s.err
+	}
+	return &CollectionResponse{ResponseBody: ResponseBody{Code: http.StatusAccepted}}, nil, nil
+}
+
+type SubscriptionsTestSuite struct {
+	suite.Suite
+	start     time.Time
+	creator   *stubCollectionCreator
+	storage   *SubscriptionMemoryStorage
+	statuses  []SubscriptionStatusCode
+	failures  int
+	completed int
+	testable  *SubscriptionScheduler
+}
+
+func (suite *SubscriptionsTestSuite) SetupTest() {
+	suite.start = time.Date(2022, 1, 31, 9, 0, 0, 0, time.UTC)
+	suite.creator = &stubCollectionCreator{}
+	suite.storage = NewSubscriptionMemoryStorage()
+	suite.statuses = nil
+	suite.failures = 0
+	suite.completed = 0
+	hooks := &SubscriptionHooks{
+		OnChargeFailed: func(subscription *Subscription, err error) {
+			suite.failures++
+		},
+		OnChargeCompleted: func(subscription *Subscription, webhook *CollectionWebhook) {
+			suite.completed++
+		},
+		OnStatusChanged: func(subscription *Subscription, from SubscriptionStatusCode, to SubscriptionStatusCode) {
+			suite.statuses = append(suite.statuses, to)
+		},
+	}
+	suite.testable = NewSubscriptionScheduler(suite.creator, suite.storage, hooks)
+	_ = suite.testable.CreatePlan(&SubscriptionPlan{
+		ID:         "basic",
+		Currency:   CurrencyCodeUGX,
+		Amount:     10000,
+		Method:     TransactionMethodMobileMoney,
+		Narration:  "Basic plan",
+		Interval:   SubscriptionIntervalMonthly,
+		MaxRetries: 2,
+		RetryDelay: time.Hour,
+	})
+	_ = suite.testable.CreateCustomer(&SubscriptionCustomer{ID: "customer", AccountNumber: "256777111786", ProviderId: "mtn_ug"})
+}
+
+func (suite *SubscriptionsTestSuite) TestCreatePlanInvalid() {
+	err := suite.testable.CreatePlan(&SubscriptionPlan{ID: "wrong", Currency: CurrencyCodeUGX, Amount: 1, Method: TransactionMethodMobileMoney, Narration: "n", Interval: "YEARLY"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `SubscriptionScheduler.CreatePlan error: parameter "interval" is invalid`, err.Error())
+	err = suite.testable.CreatePlan(&SubscriptionPlan{ID: "wrong", Currency: CurrencyCodeUGX, Amount: -1, Method: TransactionMethodMobileMoney, Narration: "n", Interval: SubscriptionIntervalDaily})
+	assert.Equal(suite.T(), `SubscriptionScheduler.CreatePlan error: parameter "amount" is negative`, err.Error())
+	err = suite.testable.CreatePlan(&SubscriptionPlan{ID: "wrong", Currency: CurrencyCodeUGX, Amount: 1, Method: "CASH", Narration: "n", Interval: SubscriptionIntervalDaily})
+	assert.Equal(suite.T(), `SubscriptionScheduler.CreatePlan error: parameter "method" is invalid`, err.Error())
+}
+
+func (suite *SubscriptionsTestSuite) TestCreateCustomerInvalid() {
+	err := suite.testable.CreateCustomer(&SubscriptionCustomer{ID: "customer"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `SubscriptionScheduler.CreateCustomer error: parameter "account_number" is empty`, err.Error())
+}
+
+func (suite *SubscriptionsTestSuite) TestSubscribeUnknownPlan() {
+	result, err := suite.testable.Subscribe("sub1", "unknown", "customer", suite.start)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), `SubscriptionScheduler.Subscribe error: subscription plan "unknown" not found`, err.Error())
+}
+
+func (suite *SubscriptionsTestSuite) TestPlanBillingAt() {
+	plan := &SubscriptionPlan{Interval: SubscriptionIntervalMonthly}
+	assert.Equal(suite.T(), suite.start, plan.BillingAt(suite.start, 1))
+	assert.Equal(suite.T(), time.Date(2022, 2, 28, 9, 0, 0, 0, time.UTC), plan.BillingAt(suite.start, 2))
+	assert.Equal(suite.T(), time.Date(2022, 3, 31, 9, 0, 0, 0, time.UTC), plan.BillingAt(suite.start, 3))
+	assert.Equal(suite.T(), time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC), plan.BillingAt(suite.start, 13))
+	plan = &SubscriptionPlan{Interval: SubscriptionIntervalWeekly, IntervalCount: 2}
+	assert.Equal(suite.T(), suite.start.AddDate(0, 0, 14), plan.BillingAt(suite.start, 2))
+	plan = &SubscriptionPlan{Interval: SubscriptionIntervalDaily}
+	assert.Equal(suite.T(), suite.start.AddDate(0, 0, 1), plan.BillingAt(suite.start, 2))
+	assert.Equal(suite.T(), DefaultSubscriptionRetryDelay, plan.GetRetryDelay())
+}
+
+func (suite *SubscriptionsTestSuite) TestRunChargeAndComplete() {
+	_, _ = suite.testable.Subscribe("sub1", "basic", "customer", suite.start)
+
+	//not due yet
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start.Add(-time.Minute)))
+	assert.Len(suite.T(), suite.creator.requests, 0)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start))
+	assert.Len(suite.T(), suite.creator.requests, 1)
+	req := suite.creator.requests[0]
+	assert.Equal(suite.T(), "sub-sub1-0001-0", req.MerchantReference)
+	assert.Equal(suite.T(), "256777111786", req.AccountNumber)
+	assert.Equal(suite.T(), "mtn_ug", req.ProviderId)
+	assert.Equal(suite.T(), 10000.0, req.Amount)
+
+	//waiting for the webhook, no double charge
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start.Add(time.Hour)))
+	assert.Len(suite.T(), suite.creator.requests, 1)
+
+	webhook := &CollectionWebhook{MerchantReference: "sub-sub1-0001-0", TransactionStatus: "COMPLETED"}
+	assert.NoError(suite.T(), suite.testable.HandleCollectionWebhook(webhook, suite.start.Add(time.Hour)))
+	subscription, _ := suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), 2, subscription.Cycle)
+	assert.Equal(suite.T(), "", subscription.PendingReference)
+	assert.Equal(suite.T(), time.Date(2022, 2, 28, 9, 0, 0, 0, time.UTC), subscription.NextBillingAt)
+	assert.Equal(suite.T(), 1, suite.completed)
+	assert.Equal(suite.T(), "sub-sub1-0002-0", subscription.MerchantReference())
+}
+
+func (suite *SubscriptionsTestSuite) TestRunDunningRetries() {
+	_, _ = suite.testable.Subscribe("sub1", "basic", "customer", suite.start)
+	suite.creator.response = &CollectionResponse{ResponseBody: ResponseBody{Code: http.StatusBadRequest, Message: "Insufficient funds"}}
+	suite.creator.err = errors.New("Insufficient funds")
+
+	now := suite.start
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), now))
+	subscription, _ := suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusPastDue, subscription.Status)
+	assert.Equal(suite.T(), 1, subscription.Attempt)
+	assert.Equal(suite.T(), now.Add(time.Hour), subscription.NextBillingAt)
+	assert.Equal(suite.T(), "Insufficient funds", subscription.LastError)
+
+	now = now.Add(time.Hour)
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), now))
+	now = now.Add(time.Hour)
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), now))
+	subscription, _ = suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusUnpaid, subscription.Status)
+	assert.Equal(suite.T(), 3, suite.failures)
+	assert.Equal(suite.T(), []SubscriptionStatusCode{SubscriptionStatusPastDue, SubscriptionStatusUnpaid}, suite.statuses)
+	assert.Equal(suite.T(), "sub-sub1-0001-0", suite.creator.requests[0].MerchantReference)
+	assert.Equal(suite.T(), "sub-sub1-0001-1", suite.creator.requests[1].MerchantReference)
+	assert.Equal(suite.T(), "sub-sub1-0001-2", suite.creator.requests[2].MerchantReference)
+
+	//unpaid subscriptions are not charged anymore
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), now.Add(time.Hour)))
+	assert.Len(suite.T(), suite.creator.requests, 3)
+}
+
+func (suite *SubscriptionsTestSuite) TestTransportErrorKeepsReferencePending() {
+	_, _ = suite.testable.Subscribe("sub1", "basic", "customer", suite.start)
+	suite.creator.err = errors.New("connection reset by peer")
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start))
+	subscription, _ := suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusActive, subscription.Status)
+	assert.Equal(suite.T(), 0, subscription.Attempt)
+	assert.Equal(suite.T(), "sub-sub1-0001-0", subscription.PendingReference)
+	assert.Equal(suite.T(), "connection reset by peer", subscription.LastError)
+	assert.Equal(suite.T(), 0, suite.failures)
+
+	//no new attempt while the result is unknown
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start.Add(time.Hour)))
+	assert.Len(suite.T(), suite.creator.requests, 1)
+
+	webhook := &CollectionWebhook{MerchantReference: "sub-sub1-0001-0", TransactionStatus: "COMPLETED"}
+	assert.NoError(suite.T(), suite.testable.HandleCollectionWebhook(webhook, suite.start.Add(time.Hour)))
+	subscription, _ = suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), 2, subscription.Cycle)
+	assert.Equal(suite.T(), "", subscription.LastError)
+}
+
+func (suite *SubscriptionsTestSuite) TestInvalidCollectionRequestIsFailed() {
+	_ = suite.testable.CreatePlan(&SubscriptionPlan{ID: "card", Currency: CurrencyCodeUGX, Amount: 10000, Method: TransactionMethodCard, Narration: "Card plan", Interval: SubscriptionIntervalMonthly})
+	//card collections require redirect url which is not set for the customer
+	_, _ = suite.testable.Subscribe("sub1", "card", "customer", suite.start)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start))
+	assert.Len(suite.T(), suite.creator.requests, 0)
+	subscription, _ := suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusUnpaid, subscription.Status)
+	assert.Equal(suite.T(), "", subscription.PendingReference)
+	assert.Equal(suite.T(), `parameter "redirect_url" is empty`, subscription.LastError)
+	assert.Equal(suite.T(), 1, suite.failures)
+}
+
+func (suite *SubscriptionsTestSuite) TestPendingChargeExpired() {
+	suite.testable.PendingTimeout = 2 * time.Hour
+	_, _ = suite.testable.Subscribe("sub1", "basic", "customer", suite.start)
+	suite.creator.err = errors.New("connection reset by peer")
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start))
+	subscription, _ := suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), suite.start.Add(2*time.Hour), subscription.PendingExpireAt)
+
+	//still waiting for the webhook
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start.Add(time.Hour)))
+	assert.Len(suite.T(), suite.creator.requests, 1)
+	assert.Equal(suite.T(), 0, suite.failures)
+
+	now := suite.start.Add(2 * time.Hour)
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), now))
+	subscription, _ = suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusPastDue, subscription.Status)
+	assert.Equal(suite.T(), "", subscription.PendingReference)
+	assert.Equal(suite.T(), `pending collection "sub-sub1-0001-0" is expired`, subscription.LastError)
+	assert.Equal(suite.T(), now.Add(time.Hour), subscription.NextBillingAt)
+	assert.Equal(suite.T(), 1, suite.failures)
+
+	//the late webhook of the expired reference is not applied
+	webhook := &CollectionWebhook{MerchantReference: "sub-sub1-0001-0", TransactionStatus: "COMPLETED"}
+	assert.Error(suite.T(), suite.testable.HandleCollectionWebhook(webhook, now))
+}
+
+func (suite *SubscriptionsTestSuite) TestFailedWebhookRecovery() {
+	_, _ = suite.testable.Subscribe("sub1", "basic", "customer", suite.start)
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start))
+
+	webhook := &CollectionWebhook{MerchantReference: "sub-sub1-0001-0", TransactionStatus: "FAILED", Message: "Transaction Failed"}
+	assert.NoError(suite.T(), suite.testable.HandleCollectionWebhook(webhook, suite.start))
+	subscription, _ := suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusPastDue, subscription.Status)
+
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start.Add(time.Hour)))
+	webhook = &CollectionWebhook{MerchantReference: "sub-sub1-0001-1", TransactionStatus: "COMPLETED"}
+	assert.NoError(suite.T(), suite.testable.HandleCollectionWebhook(webhook, suite.start.Add(time.Hour)))
+	subscription, _ = suite.storage.GetSubscription("sub1")
+	assert.Equal(suite.T(), SubscriptionStatusActive, subscription.Status)
+	assert.Equal(suite.T(), 0, subscription.Attempt)
+	assert.Equal(suite.T(), []SubscriptionStatusCode{SubscriptionStatusPastDue, SubscriptionStatusActive}, suite.statuses)
+}
+
+func (suite *SubscriptionsTestSuite) TestHandleUnknownWebhook() {
+	err := suite.testable.HandleCollectionWebhook(&CollectionWebhook{MerchantReference: "foo"}, suite.start)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `SubscriptionScheduler.HandleCollectionWebhook error: subscription with reference "foo" not found`, err.Error())
+}
+
+func (suite *SubscriptionsTestSuite) TestCancel() {
+	_, _ = suite.testable.Subscribe("sub1", "basic", "customer", suite.start)
+	assert.NoError(suite.T(), suite.testable.Cancel("sub1"))
+	assert.NoError(suite.T(), suite.testable.Run(context.Background(), suite.start))
+	assert.Len(suite.T(), suite.creator.requests, 0)
+	assert.Equal(suite.T(), []SubscriptionStatusCode{SubscriptionStatusCancelled}, suite.statuses)
+	assert.Error(suite.T(), suite.testable.Cancel("unknown"))
+}
+
+func TestSubscriptionsTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionsTestSuite))
+}
@@ -0,0 +1,154 @@
+package dusupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//WalletTransferRequest funds movement between own currency wallets
+type WalletTransferRequest struct {
+	FromCurrency      CurrencyCode `json:"from_currency"`
+	ToCurrency        CurrencyCode `json:"to_currency"`
+	Amount            float64      `json:"amount"`
+	MerchantReference string       `json:"merchant_reference"`
+	Narration         string       `json:"narration"`
+}
+
+//Check is valid WalletTransferRequest parameters
+func (wtr *WalletTransferRequest) isValid() error {
+	var err error
+	if wtr.FromCurrency == "" {
+		err = fmt.Errorf(`parameter "from_currency" is empty`)
+	} else if wtr.ToCurrency == "" {
+		err = fmt.Errorf(`parameter "to_currency" is empty`)
+	} else if wtr.FromCurrency == wtr.ToCurrency {
+		err = fmt.Errorf(`parameters "from_currency" and "to_currency" are equal`)
+	} else if wtr.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	} else if wtr.Amount < 0 {
+		err = fmt.Errorf(`parameter "amount" is negative`)
+	} else if wtr.MerchantReference == "" {
+		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
+	} else if wtr.Narration == "" {
+		err = fmt.Errorf(`parameter "narration" is empty`)
+	}
+	return err
+}
+
+//ValidateBalance check is source wallet balance is sufficient for the transfer
+func (wtr *WalletTransferRequest) ValidateBalance(balances *BalancesResponseData) error {
+	return balances.CheckSufficientBalance(wtr.FromCurrency, wtr.Amount)
+}
+
+//MerchantTransferRequest funds movement to other Dusupay merchant
+type MerchantTransferRequest struct {
+	Currency            CurrencyCode `json:"currency"`
+	Amount              float64      `json:"amount"`
+	RecipientMerchantId string       `json:"recipient_merchant_id"`
+	MerchantReference   string       `json:"merchant_reference"`
+	Narration           string       `json:"narration"`
+}
+
+//Check is valid MerchantTransferRequest parameters
+func (mtr *MerchantTransferRequest) isValid() error {
+	var err error
+	if mtr.Currency == "" {
+		err = fmt.Errorf(`parameter "currency" is empty`)
+	} else if mtr.Amount == 0 {
+		err = fmt.Errorf(`parameter "amount" is empty`)
+	} else if mtr.Amount < 0 {
+		err = fmt.Errorf(`parameter "amount" is negative`)
+	} else if mtr.RecipientMerchantId == "" {
+		err = fmt.Errorf(`parameter "recipient_merchant_id" is empty`)
+	} else if mtr.MerchantReference == "" {
+		err = fmt.Errorf(`parameter "merchant_reference" is empty`)
+	} else if mtr.Narration == "" {
+		err = fmt.Errorf(`parameter "narration" is empty`)
+	}
+	return err
+}
+
+//ValidateBalance check is wallet balance is sufficient for the transfer
+func (mtr *MerchantTransferRequest) ValidateBalance(balances *BalancesResponseData) error {
+	return balances.CheckSufficientBalance(mtr.Currency, mtr.Amount)
+}
+
+//TransferResponse struct
+type TransferResponse struct {
+	ResponseBody
+	Data *TransferResponseData `json:"data,omitempty"`
+}
+
+//TransferResponseData struct
+type TransferResponseData struct {
+	ID                  int64   `json:"id"`
+	RequestAmount       float64 `json:"request_amount"`
+	RequestCurrency     string  `json:"request_currency"`
+	AccountAmount       float64 `json:"account_amount"`
+	AccountCurrency     string  `json:"account_currency"`
+	TransactionFee      float64 `json:"transaction_fee"`
+	TotalDebit          float64 `json:"total_debit"`
+	RecipientMerchantID string  `json:"recipient_merchant_id"`
+	MerchantReference   string  `json:"merchant_reference"`
+	InternalReference   string  `json:"internal_reference"`
+	TransactionStatus   string  `json:"transaction_status"`
+	TransactionType     string  `json:"transaction_type"`
+	Message             string  `json:"message"`
+}
+
+//TransfersResource wrapper
+type TransfersResource struct {
+	ResourceAbstract
+}
+
+//CreateWalletTransfer move funds between own currency wallets (see https://docs.dusupay.com/appendix/transfers)
+func (r *TransfersResource) CreateWalletTransfer(ctx context.Context, req *WalletTransferRequest) (*TransferResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.CreateWalletTransfer error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.CreateWalletTransfer error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/transfers/wallet", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.CreateWalletTransfer error: %v", err)
+	}
+	var result TransferResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("TransfersResource.CreateWalletTransfer error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
+
+//CreateMerchantTransfer move funds to other Dusupay merchant (see https://docs.dusupay.com/appendix/transfers)
+func (r *TransfersResource) CreateMerchantTransfer(ctx context.Context, req *MerchantTransferRequest) (*TransferResponse, *http.Response, error) {
+	err := req.isValid()
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.CreateMerchantTransfer error: %v", err)
+	}
+	post, err := transformStructToMap(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.CreateMerchantTransfer error: %v", err)
+	}
+	rsp, err := r.ResourceAbstract.tr.Post(ctx, "v1/transfers/merchant", post, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("TransfersResource.CreateMerchantTransfer error: %v", err)
+	}
+	var result TransferResponse
+	err = unmarshalResponse(rsp, &result)
+	if err != nil {
+		return nil, rsp, fmt.Errorf("TransfersResource.CreateMerchantTransfer error: %v", err)
+	}
+	if !result.IsSuccess() {
+		err = errors.New(result.Message)
+	}
+	return &result, rsp, err
+}
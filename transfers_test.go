@@ -0,0 +1,212 @@
+package dusupay
+
+import (
+	"context"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type TransfersTestSuite struct {
+	suite.Suite
+	balances *BalancesResponseData
+}
+
+func (suite *TransfersTestSuite) SetupTest() {
+	suite.balances = &BalancesResponseData{
+		{Currency: "UGX", Balance: 5475.816},
+		{Currency: "USD", Balance: 12},
+	}
+}
+
+func (suite *TransfersTestSuite) TestWalletTransferRequestIsValid() {
+	cases := map[string]WalletTransferRequest{
+		`parameter "from_currency" is empty`:                     {ToCurrency: CurrencyCodeUGX, Amount: 10, MerchantReference: "ref", Narration: "narration"},
+		`parameter "to_currency" is empty`:                       {FromCurrency: CurrencyCodeUSD, Amount: 10, MerchantReference: "ref", Narration: "narration"},
+		`parameters "from_currency" and "to_currency" are equal`: {FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUSD, Amount: 10, MerchantReference: "ref", Narration: "narration"},
+		`parameter "amount" is empty`:                            {FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, MerchantReference: "ref", Narration: "narration"},
+		`parameter "amount" is negative`:                         {FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, Amount: -1, MerchantReference: "ref", Narration: "narration"},
+		`parameter "merchant_reference" is empty`:                {FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, Amount: 10, Narration: "narration"},
+		`parameter "narration" is empty`:                         {FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, Amount: 10, MerchantReference: "ref"},
+	}
+	for message, request := range cases {
+		result := request.isValid()
+		assert.Error(suite.T(), result)
+		assert.Equal(suite.T(), message, result.Error())
+	}
+	request := WalletTransferRequest{FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, Amount: 10, MerchantReference: "ref", Narration: "narration"}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *TransfersTestSuite) TestMerchantTransferRequestIsValid() {
+	cases := map[string]MerchantTransferRequest{
+		`parameter "currency" is empty`:              {Amount: 10, RecipientMerchantId: "id", MerchantReference: "ref", Narration: "narration"},
+		`parameter "amount" is empty`:                {Currency: CurrencyCodeUGX, RecipientMerchantId: "id", MerchantReference: "ref", Narration: "narration"},
+		`parameter "amount" is negative`:             {Currency: CurrencyCodeUGX, Amount: -5, RecipientMerchantId: "id", MerchantReference: "ref", Narration: "narration"},
+		`parameter "recipient_merchant_id" is empty`: {Currency: CurrencyCodeUGX, Amount: 10, MerchantReference: "ref", Narration: "narration"},
+		`parameter "merchant_reference" is empty`:    {Currency: CurrencyCodeUGX, Amount: 10, RecipientMerchantId: "id", Narration: "narration"},
+		`parameter "narration" is empty`:             {Currency: CurrencyCodeUGX, Amount: 10, RecipientMerchantId: "id", MerchantReference: "ref"},
+	}
+	for message, request := range cases {
+		result := request.isValid()
+		assert.Error(suite.T(), result)
+		assert.Equal(suite.T(), message, result.Error())
+	}
+	request := MerchantTransferRequest{Currency: CurrencyCodeUGX, Amount: 10, RecipientMerchantId: "id", MerchantReference: "ref", Narration: "narration"}
+	assert.NoError(suite.T(), request.isValid())
+}
+
+func (suite *TransfersTestSuite) TestWalletTransferRequestValidateBalance() {
+	request := &WalletTransferRequest{FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, Amount: 10}
+	assert.NoError(suite.T(), request.ValidateBalance(suite.balances))
+	request.Amount = 12.5
+	err := request.ValidateBalance(suite.balances)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `insufficient "USD" balance: 12 < 12.5`, err.Error())
+}
+
+func (suite *TransfersTestSuite) TestMerchantTransferRequestValidateBalance() {
+	request := &MerchantTransferRequest{Currency: CurrencyCodeUGX, Amount: 5000}
+	assert.NoError(suite.T(), request.ValidateBalance(suite.balances))
+	request.Currency = CurrencyCodeKES
+	err := request.ValidateBalance(suite.balances)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), `balance "KES" not found`, err.Error())
+}
+
+func TestTransfersTestSuite(t *testing.T) {
+	suite.Run(t, new(TransfersTestSuite))
+}
+
+type TransfersResourceTestSuite struct {
+	suite.Suite
+	cfg      *Config
+	ctx      context.Context
+	testable *TransfersResource
+}
+
+func (suite *TransfersResourceTestSuite) SetupTest() {
+	cfg := BuildStubConfig()
+	transport := BuildStubHttpTransport()
+	suite.cfg = cfg
+	suite.ctx = context.Background()
+	suite.testable = &TransfersResource{NewResourceAbstract(transport, cfg)}
+	httpmock.Activate()
+}
+
+func (suite *TransfersResourceTestSuite) TearDownTest() {
+	httpmock.DeactivateAndReset()
+}
+
+func (suite *TransfersResourceTestSuite) buildWalletTransferRequest() *WalletTransferRequest {
+	return &WalletTransferRequest{FromCurrency: CurrencyCodeUSD, ToCurrency: CurrencyCodeUGX, Amount: 10, MerchantReference: "transfer-1001", Narration: "narration"}
+}
+
+func (suite *TransfersResourceTestSuite) buildMerchantTransferRequest() *MerchantTransferRequest {
+	return &MerchantTransferRequest{Currency: CurrencyCodeUGX, Amount: 5000, RecipientMerchantId: "MERCHANT-2002", MerchantReference: "transfer-1002", Narration: "narration"}
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateWalletTransferSuccess() {
+	body, _ := LoadStubResponseData("stubs/transfers/wallet/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/transfers/wallet", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.CreateWalletTransfer(suite.ctx, suite.buildWalletTransferRequest())
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), http.StatusAccepted, result.Code)
+	assert.Equal(suite.T(), int64(124470), result.Data.ID)
+	assert.Equal(suite.T(), float64(10), result.Data.RequestAmount)
+	assert.Equal(suite.T(), "USD", result.Data.RequestCurrency)
+	assert.Equal(suite.T(), 36899.67, result.Data.AccountAmount)
+	assert.Equal(suite.T(), "UGX", result.Data.AccountCurrency)
+	assert.Equal(suite.T(), float64(10), result.Data.TotalDebit)
+	assert.Equal(suite.T(), "transfer-1001", result.Data.MerchantReference)
+	assert.Equal(suite.T(), "DUSUPAY405GZMTRANSFER01", result.Data.InternalReference)
+	assert.Equal(suite.T(), "COMPLETED", result.Data.TransactionStatus)
+	assert.Equal(suite.T(), "transfer", result.Data.TransactionType)
+	//response
+	defer resp.Body.Close()
+	bodyRsp, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(suite.T(), body, bodyRsp)
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateWalletTransferJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/transfers/wallet", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.CreateWalletTransfer(suite.ctx, suite.buildWalletTransferRequest())
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Empty(suite.T(), result.Data)
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateWalletTransferNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/transfers/wallet", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.CreateWalletTransfer(suite.ctx, suite.buildWalletTransferRequest())
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateWalletTransferInvalidRequest() {
+	result, rsp, err := suite.testable.CreateWalletTransfer(suite.ctx, &WalletTransferRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateMerchantTransferSuccess() {
+	body, _ := LoadStubResponseData("stubs/transfers/merchant/success.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/transfers/merchant", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.CreateMerchantTransfer(suite.ctx, suite.buildMerchantTransferRequest())
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	//result
+	assert.True(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), int64(124471), result.Data.ID)
+	assert.Equal(suite.T(), "MERCHANT-2002", result.Data.RecipientMerchantID)
+	assert.Equal(suite.T(), float64(5000), result.Data.TotalDebit)
+	assert.Equal(suite.T(), "PENDING", result.Data.TransactionStatus)
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateMerchantTransferJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/401.json")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/transfers/merchant", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.CreateMerchantTransfer(suite.ctx, suite.buildMerchantTransferRequest())
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.False(suite.T(), result.IsSuccess())
+	assert.Equal(suite.T(), "Unauthorized API access. Unknown Merchant", err.Error())
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateMerchantTransferNonJsonError() {
+	body, _ := LoadStubResponseData("stubs/errors/500.html")
+	httpmock.RegisterResponder(http.MethodPost, suite.cfg.Uri+"/v1/transfers/merchant", httpmock.NewBytesResponder(http.StatusOK, body))
+
+	result, resp, err := suite.testable.CreateMerchantTransfer(suite.ctx, suite.buildMerchantTransferRequest())
+	assert.Error(suite.T(), err)
+	assert.NotEmpty(suite.T(), resp)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *TransfersResourceTestSuite) TestCreateMerchantTransferInvalidRequest() {
+	result, rsp, err := suite.testable.CreateMerchantTransfer(suite.ctx, &MerchantTransferRequest{})
+	assert.Nil(suite.T(), rsp)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func TestTransfersResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(TransfersResourceTestSuite))
+}
@@ -0,0 +1,119 @@
+package dusupay
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//ValidationReason request parameter validation error reason
+type ValidationReason string
+
+//ValidationReasonMissing required parameter is empty
+const ValidationReasonMissing ValidationReason = "missing"
+
+//ValidationReasonOutOfRange parameter value is out of the allowed range
+const ValidationReasonOutOfRange ValidationReason = "out_of_range"
+
+//ValidationReasonBadFormat parameter value has wrong format
+const ValidationReasonBadFormat ValidationReason = "bad_format"
+
+//ValidationReasonInvalidEnum parameter value is not one of the supported values
+const ValidationReasonInvalidEnum ValidationReason = "invalid_enum"
+
+//ValidationReasonNotAllowed parameter is not allowed in combination with the other parameters
+const ValidationReasonNotAllowed ValidationReason = "not_allowed"
+
+//validationCurrencyRegexp ISO 4217 currency code
+var validationCurrencyRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+//validationCountryRegexp ISO 3166-1 alpha-2 country code
+var validationCountryRegexp = regexp.MustCompile(`^[a-zA-Z]{2}$`)
+
+//validationEmailRegexp simplified email address
+var validationEmailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+//ValidationError request parameter validation error
+type ValidationError struct {
+	//parameter JSON name (nested parameters are dot separated, e.g. "crypto.asset")
+	Field   string
+	Reason  ValidationReason
+	Message string
+}
+
+//Error error interface implementation
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(`parameter "%s" %s`, e.Field, e.Message)
+}
+
+//ValidationErrors all request parameters validation errors
+type ValidationErrors []*ValidationError
+
+//Error error interface implementation
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, len(ve))
+	for i, err := range ve {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+//HasField check is there an error of the parameter
+func (ve ValidationErrors) HasField(field string) bool {
+	for _, err := range ve {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+//ByField get errors grouped by the parameter
+func (ve ValidationErrors) ByField() map[string]ValidationErrors {
+	result := make(map[string]ValidationErrors)
+	for _, err := range ve {
+		result[err.Field] = append(result[err.Field], err)
+	}
+	return result
+}
+
+//addIf add parameter error when the check is failed
+func (ve *ValidationErrors) addIf(failed bool, field string, reason ValidationReason, format string, args ...interface{}) {
+	if failed {
+		*ve = append(*ve, &ValidationError{Field: field, Reason: reason, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+//first get first error (nil when there are no errors)
+func (ve ValidationErrors) first() error {
+	if len(ve) == 0 {
+		return nil
+	}
+	return ve[0]
+}
+
+//orNil get errors as error (nil when there are no errors)
+func (ve ValidationErrors) orNil() error {
+	if len(ve) == 0 {
+		return nil
+	}
+	return ve
+}
+
+//isValidTransactionMethod check is supported transaction method (case insensitive)
+func isValidTransactionMethod(method TransactionMethodCode) bool {
+	switch TransactionMethodCode(strings.ToUpper(string(method))) {
+	case TransactionMethodMobileMoney, TransactionMethodCard, TransactionMethodBank, TransactionMethodCrypto:
+		return true
+	}
+	return false
+}
+
+//isValidFilterTransactionType check is transaction type supported by the providers and banks lists (case insensitive)
+func isValidFilterTransactionType(transactionType TransactionTypeCode) bool {
+	switch TransactionTypeCode(strings.ToUpper(string(transactionType))) {
+	case TransactionTypeCollection, TransactionTypePayout:
+		return true
+	}
+	return false
+}
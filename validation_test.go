@@ -0,0 +1,118 @@
+package dusupay
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ValidationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ValidationTestSuite) assertReasons(errs ValidationErrors, expected map[string]ValidationReason) {
+	result := make(map[string]ValidationReason, len(errs))
+	for _, err := range errs {
+		result[err.Field] = err.Reason
+	}
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *ValidationTestSuite) TestValidationErrors() {
+	errs := ValidationErrors{
+		{Field: "amount", Reason: ValidationReasonMissing, Message: "is empty"},
+		{Field: "currency", Reason: ValidationReasonBadFormat, Message: `value "usd" is not ISO 4217 currency code`},
+		{Field: "amount", Reason: ValidationReasonOutOfRange, Message: "is negative"},
+	}
+	assert.Equal(suite.T(), `parameter "amount" is empty; parameter "currency" value "usd" is not ISO 4217 currency code; parameter "amount" is negative`, errs.Error())
+	assert.True(suite.T(), errs.HasField("currency"))
+	assert.False(suite.T(), errs.HasField("method"))
+	assert.Len(suite.T(), errs.ByField()["amount"], 2)
+	assert.Equal(suite.T(), errs[0], errs.first())
+	assert.Nil(suite.T(), ValidationErrors{}.first())
+	assert.Nil(suite.T(), ValidationErrors{}.orNil())
+}
+
+func (suite *ValidationTestSuite) TestCollectionRequestValidate() {
+	request := &CollectionRequest{Currency: "usd", Amount: -1, Method: "PAYPAL", MobileMoneyHpp: true, AccountEmail: "john"}
+	errs := request.Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{
+		"currency":           ValidationReasonBadFormat,
+		"amount":             ValidationReasonOutOfRange,
+		"method":             ValidationReasonInvalidEnum,
+		"provider_id":        ValidationReasonMissing,
+		"merchant_reference": ValidationReasonMissing,
+		"narration":          ValidationReasonMissing,
+		"mobile_money_hpp":   ValidationReasonNotAllowed,
+		"redirect_url":       ValidationReasonMissing,
+		"account_email":      ValidationReasonBadFormat,
+	})
+	assert.Equal(suite.T(), `parameter "currency" value "usd" is not ISO 4217 currency code`, request.isValid().Error())
+}
+
+func (suite *ValidationTestSuite) TestCollectionRequestValidateCrypto() {
+	request := &CollectionRequest{Currency: CurrencyCodeUSD, Amount: 10, Method: TransactionMethodCrypto, ProviderId: "crypto", MerchantReference: "ref", Narration: "foo",
+		RedirectUrl: "https://www.sample-url.com/redirect", Crypto: &CryptoParams{Asset: CryptoAssetBTC, Network: "SOLANA"}}
+	errs := request.Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{"crypto.network": ValidationReasonInvalidEnum})
+}
+
+func (suite *ValidationTestSuite) TestPayoutRequestValidate() {
+	request := &PayoutRequest{Currency: CurrencyCodeUGX, Amount: 1000, Method: TransactionMethodMobileMoney, AccountEmail: "john@example"}
+	errs := request.Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{
+		"provider_id":        ValidationReasonMissing,
+		"merchant_reference": ValidationReasonMissing,
+		"narration":          ValidationReasonMissing,
+		"account_number":     ValidationReasonMissing,
+		"account_name":       ValidationReasonMissing,
+		"account_email":      ValidationReasonBadFormat,
+	})
+}
+
+func (suite *ValidationTestSuite) TestPayoutRequestValidateSuccess() {
+	request := &PayoutRequest{Currency: CurrencyCodeUGX, Amount: 1000, Method: TransactionMethodMobileMoney, ProviderId: "mtn_ug",
+		MerchantReference: "ref", Narration: "foo", AccountNumber: "256777000123", AccountName: "John Doe", AccountEmail: "john@example.com"}
+	assert.Nil(suite.T(), request.Validate())
+}
+
+func (suite *ValidationTestSuite) TestRefundRequestValidate() {
+	errs := (&RefundRequest{Amount: -5}).Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{
+		"internal_reference": ValidationReasonMissing,
+		"amount":             ValidationReasonOutOfRange,
+	})
+	assert.Nil(suite.T(), (&RefundRequest{Amount: 5, InternalReference: "DUSUPAY405GZM1G5JXGA71IK"}).Validate())
+}
+
+func (suite *ValidationTestSuite) TestProvidersFilterValidate() {
+	errs := (&ProvidersFilter{Country: "UGA", Method: "paypal", TransactionType: TransactionTypeRefund}).Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{
+		"country_code":     ValidationReasonBadFormat,
+		"method":           ValidationReasonInvalidEnum,
+		"transaction_type": ValidationReasonInvalidEnum,
+	})
+	assert.Nil(suite.T(), (&ProvidersFilter{Country: "ug", Method: "mobile_money", TransactionType: "collection"}).Validate())
+}
+
+func (suite *ValidationTestSuite) TestBanksFilterValidate() {
+	errs := (&BanksFilter{Country: "1"}).Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{
+		"country_code":     ValidationReasonBadFormat,
+		"transaction_type": ValidationReasonMissing,
+	})
+	assert.Nil(suite.T(), (&BanksFilter{Country: CountryCodeNigeria, TransactionType: TransactionTypePayout}).Validate())
+}
+
+func (suite *ValidationTestSuite) TestBanksBranchesFilterValidate() {
+	errs := (&BanksBranchesFilter{}).Validate()
+	suite.assertReasons(errs, map[string]ValidationReason{
+		"country_code": ValidationReasonMissing,
+		"bank_code":    ValidationReasonMissing,
+	})
+	assert.Equal(suite.T(), `parameter "country_code" is empty`, (&BanksBranchesFilter{}).isValid().Error())
+}
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
@@ -5,27 +5,35 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 //CollectionWebhook struct
 type CollectionWebhook struct {
-	ID                int64   `json:"id"`
-	RequestAmount     float64 `json:"request_amount"`
-	RequestCurrency   string  `json:"request_currency"`
-	AccountAmount     float64 `json:"account_amount"`
-	AccountCurrency   string  `json:"account_currency"`
-	TransactionFee    float64 `json:"transaction_fee"`
-	TotalCredit       float64 `json:"total_credit"`
-	CustomerCharged   bool    `json:"customer_charged"`
-	ProviderID        string  `json:"provider_id"`
-	MerchantReference string  `json:"merchant_reference"`
-	InternalReference string  `json:"internal_reference"`
-	TransactionStatus string  `json:"transaction_status"`
-	TransactionType   string  `json:"transaction_type"`
-	Message           string  `json:"message"`
-	AccountNumber     string  `json:"account_number"`
-	AccountName       string  `json:"account_name"`
-	InstitutionName   string  `json:"institution_name"`
+	ID                int64                  `json:"id"`
+	RequestAmount     float64                `json:"request_amount"`
+	RequestCurrency   string                 `json:"request_currency"`
+	AccountAmount     float64                `json:"account_amount"`
+	AccountCurrency   string                 `json:"account_currency"`
+	TransactionFee    float64                `json:"transaction_fee"`
+	TotalCredit       float64                `json:"total_credit"`
+	CustomerCharged   bool                   `json:"customer_charged"`
+	ProviderID        string                 `json:"provider_id"`
+	MerchantReference string                 `json:"merchant_reference"`
+	InternalReference string                 `json:"internal_reference"`
+	TransactionStatus string                 `json:"transaction_status"`
+	TransactionType   string                 `json:"transaction_type"`
+	Message           string                 `json:"message"`
+	AccountNumber     string                 `json:"account_number"`
+	AccountName       string                 `json:"account_name"`
+	InstitutionName   string                 `json:"institution_name"`
+	Crypto            *CryptoTransactionData `json:"crypto,omitempty"`
+	PaymentLinkID     string                 `json:"payment_link_id,omitempty"`
+}
+
+//IsPaymentLinkCollection check is collection was paid through the payment link
+func (cw *CollectionWebhook) IsPaymentLinkCollection() bool {
+	return cw.PaymentLinkID != ""
 }
 
 func (cw *CollectionWebhook) BuildPayloadString(url string) string {
@@ -34,22 +42,23 @@ func (cw *CollectionWebhook) BuildPayloadString(url string) string {
 
 //PayoutWebhook struct
 type PayoutWebhook struct {
-	ID                int64   `json:"id"`
-	RequestAmount     float64 `json:"request_amount"`
-	RequestCurrency   string  `json:"request_currency"`
-	AccountAmount     float64 `json:"account_amount"`
-	AccountCurrency   string  `json:"account_currency"`
-	TransactionFee    float64 `json:"transaction_fee"`
-	TotalDebit        float64 `json:"total_debit"`
-	ProviderID        string  `json:"provider_id"`
-	MerchantReference string  `json:"merchant_reference"`
-	InternalReference string  `json:"internal_reference"`
-	TransactionStatus string  `json:"transaction_status"`
-	TransactionType   string  `json:"transaction_type"`
-	Message           string  `json:"message"`
-	AccountNumber     string  `json:"account_number"`
-	AccountName       string  `json:"account_name"`
-	InstitutionName   string  `json:"institution_name"`
+	ID                int64                  `json:"id"`
+	RequestAmount     float64                `json:"request_amount"`
+	RequestCurrency   string                 `json:"request_currency"`
+	AccountAmount     float64                `json:"account_amount"`
+	AccountCurrency   string                 `json:"account_currency"`
+	TransactionFee    float64                `json:"transaction_fee"`
+	TotalDebit        float64                `json:"total_debit"`
+	ProviderID        string                 `json:"provider_id"`
+	MerchantReference string                 `json:"merchant_reference"`
+	InternalReference string                 `json:"internal_reference"`
+	TransactionStatus string                 `json:"transaction_status"`
+	TransactionType   string                 `json:"transaction_type"`
+	Message           string                 `json:"message"`
+	AccountNumber     string                 `json:"account_number"`
+	AccountName       string                 `json:"account_name"`
+	InstitutionName   string                 `json:"institution_name"`
+	Crypto            *CryptoTransactionData `json:"crypto,omitempty"`
 }
 
 func (pw *PayoutWebhook) BuildPayloadString(url string) string {
@@ -76,6 +85,11 @@ func (rw *RefundWebhook) BuildPayloadString(url string) string {
 	return fmt.Sprintf("%d:%s:%s:%s", rw.ID, rw.InternalReference, rw.TransactionStatus, url)
 }
 
+//GetStatus typed refund status
+func (rw *RefundWebhook) GetStatus() TransactionStatusCode {
+	return TransactionStatusCode(strings.ToUpper(rw.TransactionStatus))
+}
+
 //WebhookResponse struct
 type WebhookResponse struct {
 	ResponseBody